@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"strconv"
+	"strings"
+)
+
+// Analyze runs the "analyze" subcommand, which explains what limits the trains between two stations
+// Usage:
+//
+//	analyze [-network <name>] <network_map> <start_station> <end_station> <number_of_trains>
+//
+// The report lists the largest set of routes trains can follow side by side, the stations and tracks
+// of a minimum cut that limit their number, and the fewest turns any schedule of the trains needs.
+func Analyze(args []string) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	networkName := flags.String("network", "", networkUsage)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 4 {
+		return utils.ErrIncorrectArgCount
+	}
+	args = flags.Args()
+	start, end := args[1], args[2]
+
+	numTrains, err := strconv.Atoi(args[3])
+	if err != nil || numTrains <= 0 {
+		return &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: args[3]}
+	}
+
+	networks, err := io.ReadMapFile(args[0])
+	if err != nil {
+		return core.ExplainMissingStation(err, start, end)
+	}
+	name, stations, err := findNetwork(networks, *networkName, start, end)
+	if err != nil {
+		return err
+	}
+	start, end = core.ResolveStation(stations, start), core.ResolveStation(stations, end)
+
+	analysis, err := pathfinding.Analyze(start, end, stations, numTrains)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Network: %s\n", name)
+	fmt.Printf("Maximum disjoint routes: %d\n", len(analysis.Routes))
+	for i, route := range analysis.Routes {
+		fmt.Printf("  %s (%d turn(s))\n", strings.Join(route, "-"), analysis.Lengths[i])
+	}
+
+	// Platforms and track capacities explain cuts that are smaller than the number of routes
+	var cut []string
+	for _, station := range analysis.CutStations {
+		if platforms := stations[station].Capacity(); platforms > 1 {
+			cut = append(cut, fmt.Sprintf("%s (%d platforms)", station, platforms))
+		} else {
+			cut = append(cut, station)
+		}
+	}
+	for _, track := range analysis.CutTracks {
+		if capacity := stations[track[0]].TrackTo(track[1]).Capacity; capacity > 1 {
+			cut = append(cut, fmt.Sprintf("track %s-%s (capacity %d)", track[0], track[1], capacity))
+		} else {
+			cut = append(cut, fmt.Sprintf("track %s-%s", track[0], track[1]))
+		}
+	}
+	fmt.Printf("Minimum cut: %s\n", strings.Join(cut, ", "))
+	fmt.Printf("Minimum turns for %d train(s): %d\n", numTrains, analysis.MinTurns)
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/output"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"strconv"
+)
+
+// Export runs the "export" subcommand, which writes the networks of a map in another format
+// Usage:
+//
+//	export [-o <file>] [-network <name>] dot <network_map> [<start_station> <end_station> <number_of_trains>]
+//	export [-o <file>] [-network <name>] geojson <network_map>
+//
+// Without -o the result is written to standard output, and without -network every network of the map
+// is written in file order. When a start, end and number of trains are given, the paths found by
+// pathfinding.FindPaths are highlighted in the DOT graph of the network that serves them.
+func Export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	outputFile := flags.String("o", "", "File to write the export to")
+	networkName := flags.String("network", "", "Name of the network to export")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) < 1 {
+		return utils.ErrIncorrectArgCount
+	}
+	format := args[0]
+	if format != output.FormatDOT && format != output.FormatGeoJSON {
+		return &utils.ValueError{Kind: utils.ErrInvalidExportFormat, Value: format, Expected: "dot or geojson"}
+	}
+	if len(args) != 2 && (len(args) != 5 || format != output.FormatDOT) {
+		return utils.ErrIncorrectArgCount
+	}
+
+	var start, end string
+	if len(args) == 5 {
+		start, end = args[2], args[3]
+	}
+	networks, err := io.ReadMapFile(args[1])
+	if err != nil {
+		return core.ExplainMissingStation(err, start, end)
+	}
+	if networks, err = onlyNetwork(networks, *networkName); err != nil {
+		return err
+	}
+
+	// Find the paths to highlight, if any
+	var selectedNetwork string
+	var selectedStations map[string]*model.Station
+	var paths [][]string
+	if len(args) == 5 {
+		numTrains, err := strconv.Atoi(args[4])
+		if err != nil || numTrains <= 0 {
+			return &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: args[4]}
+		}
+		selectedNetwork, selectedStations, err = core.FindAppropriateMap(networks, start, end)
+		if err != nil {
+			return err
+		}
+		start, end = core.ResolveStation(selectedStations, start), core.ResolveStation(selectedStations, end)
+		if paths, _, err = pathfinding.FindPaths(start, end, selectedStations, numTrains); err != nil {
+			return err
+		}
+	}
+
+	w := os.Stdout
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == output.FormatGeoJSON {
+		return io.WriteGeoJSON(w, networks)
+	}
+
+	// Write one graph per network, in file order
+	for _, name := range networks.Order {
+		var networkPaths [][]string
+		if name == selectedNetwork {
+			networkPaths = paths
+		}
+		if err := output.WriteDOT(w, name, networks.Networks[name], networkPaths); err != nil {
+			return err
+		}
+	}
+
+	// Paths across interchanges are drawn on an extra graph of the combined networks
+	if _, exists := networks.Networks[selectedNetwork]; selectedNetwork != "" && !exists {
+		return output.WriteDOT(w, selectedNetwork, selectedStations, paths)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/utils"
+)
+
+// Lint runs the "lint" subcommand, which reports every problem in one or more map files
+// Usage:
+//
+//	lint <network_map>...
+//
+// Every diagnostic is printed as "file:line:column: severity code: message", followed by a summary.
+// An error is returned if any error-severity diagnostic was found; warnings alone do not fail.
+func Lint(args []string) error {
+	if len(args) == 0 {
+		return utils.ErrIncorrectArgCount
+	}
+
+	errors, warnings := 0, 0
+	for _, path := range args {
+		diagnostics, err := io.LintMap(path)
+		if err != nil {
+			return err
+		}
+
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic)
+			if diagnostic.Severity == model.SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	fmt.Printf("%d error(s), %d warning(s)\n", errors, warnings)
+	if errors > 0 {
+		return fmt.Errorf("%w: found %d error(s)", utils.ErrLintFailed, errors)
+	}
+	return nil
+}
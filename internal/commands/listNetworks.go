@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"fmt"
+	"station/internal/utils"
+	"station/planner"
+)
+
+// ListNetworks runs the "list-networks" subcommand, which describes the networks of a map file
+// Usage:
+//
+//	list-networks <network_map>
+//
+// Every network is printed in file order as "name: N station(s), M connection(s)". The first network
+// listed that contains the start and end stations is the one used when no -network flag is given.
+func ListNetworks(args []string) error {
+	if len(args) != 1 {
+		return utils.ErrIncorrectArgCount
+	}
+
+	networks, err := planner.OpenMap(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, network := range planner.Ordered(networks) {
+		fmt.Printf("%s: %d station(s), %d connection(s)\n", network.Name, len(network.Stations()), network.Connections())
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"station/internal/core"
+	"station/internal/model"
+	"station/internal/utils"
+)
+
+// networkUsage describes the -network flag shared by the subcommands
+const networkUsage = "Name of the network to use when several contain the stations"
+
+// findNetwork selects the network a subcommand plans on, like the -network flag of the default command
+// Parameters:
+//
+//	networks: All networks of the map file, in file order
+//	name: The value of the -network flag, or "" to use the first network holding the stations
+//	start, end: The names of the start and end stations
+//
+// Returns:
+//
+//	The name of the network, its station map, and an error if the named network does not exist or
+//	lacks a station, or if no network holds both stations (see core.FindAppropriateMap)
+func findNetwork(networks *model.Map, name, start, end string) (string, map[string]*model.Station, error) {
+	if name == "" {
+		return core.FindAppropriateMap(networks, start, end)
+	}
+	stations, err := core.FindNamedMap(networks, name, []model.Job{{Start: start, End: end}})
+	if err != nil {
+		return "", nil, err
+	}
+	return name, stations, nil
+}
+
+// onlyNetwork returns the map reduced to the network named by the -network flag, for subcommands that
+// work on every network of a map, or the map itself when no name is given
+func onlyNetwork(networks *model.Map, name string) (*model.Map, error) {
+	if name == "" {
+		return networks, nil
+	}
+	stations, exists := networks.Networks[name]
+	if !exists {
+		return nil, &utils.ValueError{Kind: utils.ErrNetworkNotExist, Value: name}
+	}
+	// Interchanges lead to the other networks, which are left out
+	return &model.Map{Networks: map[string]map[string]*model.Station{name: stations}, Order: []string{name}}, nil
+}
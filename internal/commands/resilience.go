@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/utils"
+	"station/internal/visualization"
+	"strings"
+)
+
+// Resilience runs the "resilience" subcommand, which reports the single points of failure of a network
+// Usage:
+//
+//	resilience [-v] [-network <name>] <network_map> [<start_station> <end_station>]
+//
+// Without stations, the articulation points and bridges of every network are printed in file order.
+// With stations, the network holding them is reported, followed by the stations and connections whose
+// loss cuts off the end station from the start station. The -v flag draws the network, or the first
+// one in file order, to network_visualization.png with the reported points highlighted. The -network
+// flag limits the report to the named network.
+func Resilience(args []string) error {
+	flags := flag.NewFlagSet("resilience", flag.ContinueOnError)
+	visualize := flags.Bool("v", false, "Highlight the single points of failure in network_visualization.png")
+	networkName := flags.String("network", "", networkUsage)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 && flags.NArg() != 3 {
+		return utils.ErrIncorrectArgCount
+	}
+	args = flags.Args()
+
+	if len(args) == 1 {
+		networks, err := io.ReadMapFile(args[0])
+		if err != nil {
+			return err
+		}
+		if networks, err = onlyNetwork(networks, *networkName); err != nil {
+			return err
+		}
+		for i, name := range networks.Order {
+			resilience := core.FindSinglePoints(networks.Networks[name])
+			fmt.Printf("Network: %s\n", name)
+			printResilience("", resilience)
+			if *visualize && i == 0 {
+				if err := visualization.CreateVisualization(networks.Networks[name], nil, resilience); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	start, end := args[1], args[2]
+	networks, err := io.ReadMapFile(args[0])
+	if err != nil {
+		return core.ExplainMissingStation(err, start, end)
+	}
+	name, stations, err := findNetwork(networks, *networkName, start, end)
+	if err != nil {
+		return err
+	}
+	start, end = core.ResolveStation(stations, start), core.ResolveStation(stations, end)
+
+	separating, err := core.FindSeparatingPoints(stations, start, end)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Network: %s\n", name)
+	printResilience("", core.FindSinglePoints(stations))
+	printResilience(fmt.Sprintf(" between %s and %s", start, end), separating)
+
+	if *visualize {
+		return visualization.CreateVisualization(stations, nil, separating)
+	}
+	return nil
+}
+
+// printResilience prints the articulation points and bridges of a report, with a suffix describing their scope
+func printResilience(scope string, resilience model.Resilience) {
+	bridges := make([]string, len(resilience.Bridges))
+	for i, bridge := range resilience.Bridges {
+		bridges[i] = bridge[0] + "-" + bridge[1]
+	}
+	fmt.Printf("Articulation points%s: %s\n", scope, listOrNone(resilience.ArticulationPoints))
+	fmt.Printf("Bridges%s: %s\n", scope, listOrNone(bridges))
+}
+
+// listOrNone joins names with commas, or returns "none" when there are none
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"station/internal/server"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+)
+
+// Serve runs the "serve" subcommand, which answers route planning requests over HTTP
+// Usage:
+//
+//	serve [-addr <host:port>] [<name>=]<network_map>...
+//
+// Every map is loaded at startup under the given name, or else under its file name without
+// extension, so that clients can refer to it. The endpoints are described by server.Handler.
+func Serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "Address to listen on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return utils.ErrIncorrectArgCount
+	}
+
+	maps := make(map[string]map[string]*planner.Network)
+	for _, arg := range flags.Args() {
+		name, path, found := strings.Cut(arg, "=")
+		if !found {
+			path = arg
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		networks, err := planner.OpenMap(path)
+		if err != nil {
+			return err
+		}
+		maps[name] = networks
+		fmt.Fprintf(os.Stderr, "Loaded map %s from %s (%d network(s))\n", name, path, len(networks))
+	}
+
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", *addr)
+	return server.New(maps).HTTPServer(*addr).ListenAndServe()
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"strconv"
+)
+
+// Suggest runs the "suggest" subcommand, which ranks new connections by the turns they would save
+// Usage:
+//
+//	suggest [-distance <max>] [-top <n>] [-network <name>] <network_map> <start_station> <end_station> <number_of_trains>
+//
+// Every pair of unconnected stations within the given distance on the grid is tried as a new single
+// track, and the pairs that reduce the number of turns are printed, most useful first.
+func Suggest(args []string) error {
+	flags := flag.NewFlagSet("suggest", flag.ContinueOnError)
+	maxDistance := flags.Float64("distance", 5, "Maximum distance between the stations of a new connection")
+	top := flags.Int("top", 10, "Number of suggestions to print, or 0 for all")
+	networkName := flags.String("network", "", networkUsage)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 4 {
+		return utils.ErrIncorrectArgCount
+	}
+	args = flags.Args()
+	start, end := args[1], args[2]
+
+	numTrains, err := strconv.Atoi(args[3])
+	if err != nil || numTrains <= 0 {
+		return &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: args[3]}
+	}
+
+	networks, err := io.ReadMapFile(args[0])
+	if err != nil {
+		return core.ExplainMissingStation(err, start, end)
+	}
+	name, stations, err := findNetwork(networks, *networkName, start, end)
+	if err != nil {
+		return err
+	}
+	start, end = core.ResolveStation(stations, start), core.ResolveStation(stations, end)
+
+	candidates := pathfinding.CandidateConnections(stations, *maxDistance)
+	suggestions, baseline, err := pathfinding.SuggestConnections(start, end, stations, numTrains, candidates)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Network: %s\n", name)
+	fmt.Printf("Current turns for %d train(s): %d\n", numTrains, baseline)
+	fmt.Printf("Candidates within distance %g: %d\n", *maxDistance, len(candidates))
+	if len(suggestions) == 0 {
+		fmt.Println("No new connection reduces the number of turns")
+		return nil
+	}
+
+	if *top > 0 && len(suggestions) > *top {
+		suggestions = suggestions[:*top]
+	}
+	for i, suggestion := range suggestions {
+		fmt.Printf("%d. %s-%s (distance %.1f): %d turns (-%d)\n",
+			i+1, suggestion.From, suggestion.To, suggestion.Distance, suggestion.Turns, suggestion.Reduction)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/utils"
+)
+
+// Validate runs the "validate" subcommand, which checks a movement log against a network
+// Usage:
+//
+//	validate [-network <name>] <network_map> <start_station> <end_station> <movement_log>
+//
+// Every violation is printed with its turn and train, and an error is returned if any was found.
+func Validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	networkName := flags.String("network", "", networkUsage)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 4 {
+		return utils.ErrIncorrectArgCount
+	}
+	args = flags.Args()
+	networkMapFile, start, end, logFile := args[0], args[1], args[2], args[3]
+
+	networks, err := io.ReadMapFile(networkMapFile)
+	if err != nil {
+		return core.ExplainMissingStation(err, start, end)
+	}
+
+	_, stations, err := findNetwork(networks, *networkName, start, end)
+	if err != nil {
+		return err
+	}
+	start, end = core.ResolveStation(stations, start), core.ResolveStation(stations, end)
+
+	entries, err := io.ReadMovementLog(logFile)
+	if err != nil {
+		return err
+	}
+
+	turns, violations := core.ValidateLog(entries, start, end, stations)
+	for _, violation := range violations {
+		fmt.Printf("Turn %d, T%d: %s\n", violation.Turn, violation.TrainID+1, violation.Message)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: found %d violation(s)", utils.ErrValidationFailed, len(violations))
+	}
+
+	fmt.Printf("Schedule is valid: all trains reach %s in %d turns\n", end, turns)
+	return nil
+}
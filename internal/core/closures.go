@@ -0,0 +1,69 @@
+package core
+
+import (
+	"fmt"
+	"station/internal/model"
+	"station/internal/utils"
+)
+
+// CheckClosures checks that every closed station and connection exists in the network
+func CheckClosures(stations map[string]*model.Station, closures []model.Closure) error {
+	for _, closure := range closures {
+		if closure.Station != "" {
+			if _, exists := stations[closure.Station]; !exists {
+				return &utils.StationError{Kind: utils.ErrInvalidClosure, Station: closure.Station, Detail: "the station does not exist"}
+			}
+			continue
+		}
+
+		from, exists := stations[closure.From]
+		if !exists || !IsConnected(from, closure.To) {
+			return &utils.ConnectionError{Kind: utils.ErrInvalidClosure, From: closure.From, To: closure.To, Detail: "the connection does not exist"}
+		}
+	}
+	return nil
+}
+
+// FindClosureConflicts checks the paths of all trains against closed stations and connections
+// Parameters:
+//
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	stations: A map of all stations in the network, used to look up track durations
+//	closures: The stations and connections out of service, with their turn windows
+//
+// Returns:
+//
+//	A conflict for every movement along a track that is closed during any of its turns, ordered by turn
+func FindClosureConflicts(paths [][]string, stations map[string]*model.Station, closures []model.Closure) []model.Conflict {
+	var conflicts []model.Conflict
+	for trainID, path := range paths {
+		for _, movement := range CreateMovements(path, trainID, stations) {
+			if movement.From == movement.To {
+				continue // Waiting is allowed, even at a closed station
+			}
+			if closure, turn, closed := findClosure(closures, movement.From, movement.To, movement.Depart, movement.Arrive); closed {
+				conflicts = append(conflicts, model.Conflict{
+					Turn:    turn,
+					TrainID: trainID,
+					Message: fmt.Sprintf("travels %s-%s during closure %s", movement.From, movement.To, closure),
+				})
+			}
+		}
+	}
+
+	sortConflicts(conflicts)
+	return conflicts
+}
+
+// findClosure returns the first closure blocking the track between two stations during the given turns,
+// together with the first blocked turn
+func findClosure(closures []model.Closure, from, to string, first, last int) (model.Closure, int, bool) {
+	for turn := first; turn <= last; turn++ {
+		for _, closure := range closures {
+			if closure.Closes(from, to, turn) {
+				return closure, turn, true
+			}
+		}
+	}
+	return model.Closure{}, 0, false
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"station/internal/model"
+	"strings"
+)
+
+// FindConflicts checks the paths of all trains against the rules of the network
+// Parameters:
+//
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	A slice of conflicts ordered by turn, empty if the trains can run as planned.
+//	The rules are: trains only move along existing connections, no more trains enter a track
+//	in one turn than its capacity allows, trains never meet head-on on a single track, and
+//	every station other than a train's own start and end holds at most one train per platform.
+//	Opposing trains that block each other are reported as well, see FindDeadlocks.
+func FindConflicts(paths [][]string, stations map[string]*model.Station) []model.Conflict {
+	var conflicts []model.Conflict
+
+	// Trains entering each track, keyed by turn and direction of travel
+	entering := make(map[int]map[[2]string][]int)
+	// Trains travelling along each track, keyed by turn and direction of travel
+	onTrack := make(map[int]map[[2]string][]int)
+	// Trains standing at each station, keyed by turn and station name
+	occupied := make(map[int]map[string][]int)
+
+	for trainID, path := range paths {
+		for _, movement := range CreateMovements(path, trainID, stations) {
+			from, to := movement.From, movement.To
+
+			if from != to {
+				station, exists := stations[from]
+				if !exists || !IsConnected(station, to) {
+					conflicts = append(conflicts, model.Conflict{
+						Turn:    movement.Depart,
+						TrainID: trainID,
+						Message: fmt.Sprintf("no connection between %s and %s", from, to),
+					})
+					continue
+				}
+
+				direction := [2]string{from, to}
+				addTrain(entering, movement.Depart, direction, trainID)
+				for turn := movement.Depart; turn <= movement.Arrive; turn++ {
+					addTrain(onTrack, turn, direction, trainID)
+				}
+			}
+
+			// The start and end stations of a train hold any number of trains
+			if to != path[0] && to != path[len(path)-1] {
+				addTrain(occupied, movement.Arrive, to, trainID)
+			}
+		}
+	}
+
+	for turn, tracks := range entering {
+		for direction, trains := range tracks {
+			from, to := direction[0], direction[1]
+			track := stations[from].TrackTo(to)
+
+			// Trains coming the other way during the same turn share the track as well
+			opposing := tracks[[2]string{to, from}]
+			if len(opposing) > 0 && from > to {
+				continue // Reported together with the opposite direction
+			}
+
+			// Opposing trains on a single track are reported as head-on conflicts below
+			if len(opposing) > 0 && track.Capacity == 1 {
+				continue
+			}
+
+			if len(trains)+len(opposing) > track.Capacity {
+				conflicts = append(conflicts, model.Conflict{
+					Turn:    turn,
+					TrainID: trains[len(trains)-1],
+					Message: fmt.Sprintf("track %s-%s used by %d trains, capacity is %d", from, to, len(trains)+len(opposing), track.Capacity),
+				})
+			}
+		}
+	}
+
+	// Each pair of opposing trains is reported once, in the first turn they meet
+	type meeting struct {
+		trainID, opposingID int
+		from, to            string
+	}
+	headOn := make(map[meeting]int)
+	for turn, tracks := range onTrack {
+		for direction, trains := range tracks {
+			from, to := direction[0], direction[1]
+
+			// A single track can only be used in one direction at a time
+			opposing := tracks[[2]string{to, from}]
+			if len(opposing) > 0 && from < to && stations[from].TrackTo(to).Capacity == 1 {
+				key := meeting{trains[0], opposing[0], from, to}
+				if first, seen := headOn[key]; !seen || turn < first {
+					headOn[key] = turn
+				}
+			}
+		}
+	}
+	for key, turn := range headOn {
+		conflicts = append(conflicts, model.Conflict{
+			Turn:    turn,
+			TrainID: key.trainID,
+			Message: fmt.Sprintf("head-on conflict with T%d on single track %s-%s", key.opposingID+1, key.from, key.to),
+		})
+	}
+
+	for turn, stationTrains := range occupied {
+		for station, trains := range stationTrains {
+			platforms := 1
+			if s, exists := stations[station]; exists {
+				platforms = s.Capacity()
+			}
+			if len(trains) > platforms {
+				conflicts = append(conflicts, model.Conflict{
+					Turn:    turn,
+					TrainID: trains[platforms],
+					Message: fmt.Sprintf("station %s already occupied by %s", station, TrainList(trains[:platforms])),
+				})
+			}
+		}
+	}
+
+	conflicts = append(conflicts, FindDeadlocks(paths, stations)...)
+	sortConflicts(conflicts)
+	return conflicts
+}
+
+// addTrain records a train under the given turn and key
+func addTrain[K comparable](index map[int]map[K][]int, turn int, key K, trainID int) {
+	if index[turn] == nil {
+		index[turn] = make(map[K][]int)
+	}
+	index[turn][key] = append(index[turn][key], trainID)
+}
+
+// IsConnected reports whether station has a direct connection to the station with the given name
+func IsConnected(station *model.Station, name string) bool {
+	for _, conn := range station.Connections {
+		if conn.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortConflicts orders conflicts by turn and train so that reports are deterministic
+func sortConflicts(conflicts []model.Conflict) {
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Turn != conflicts[j].Turn {
+			return conflicts[i].Turn < conflicts[j].Turn
+		}
+		if conflicts[i].TrainID != conflicts[j].TrainID {
+			return conflicts[i].TrainID < conflicts[j].TrainID
+		}
+		return conflicts[i].Message < conflicts[j].Message
+	})
+}
+
+// TrainList formats train identifiers as a comma separated list of "T<n>" names, e.g. "T1, T3"
+func TrainList(trains []int) string {
+	names := make([]string, len(trains))
+	for i, trainID := range trains {
+		names[i] = fmt.Sprintf("T%d", trainID+1)
+	}
+	return strings.Join(names, ", ")
+}
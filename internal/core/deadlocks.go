@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"station/internal/model"
+)
+
+// FindDeadlocks looks for opposing trains that block each other on a single track
+// Parameters:
+//
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	A conflict for every pair of deadlocked trains, in the first turn they block each other.
+//	Two trains are deadlocked when both wait at the ends of a single track to travel towards each
+//	other, and neither can move first because the station it heads for has no free platform:
+//	any way out of that state is a head-on conflict or an overfull station.
+func FindDeadlocks(paths [][]string, stations map[string]*model.Station) []model.Conflict {
+	// waiter is a train waiting at a station for the turn, before travelling on to its next station
+	type waiter struct {
+		trainID int
+		next    string
+	}
+	// Waiting trains and trains standing at each station, keyed by turn and station name
+	waiting := make(map[int]map[string][]waiter)
+	occupied := make(map[int]map[string]int)
+
+	for trainID, path := range paths {
+		occupations := CreateOccupations(path, trainID, stations)
+
+		// The station each occupation is left for, found backwards so that long waits take linear time
+		leftFor := make([]string, len(occupations))
+		for k := len(occupations) - 2; k >= 0; k-- {
+			if occupations[k+1].Station != occupations[k].Station {
+				leftFor[k] = occupations[k+1].Station
+			} else {
+				leftFor[k] = leftFor[k+1]
+			}
+		}
+
+		for k, occupation := range occupations {
+			// Like in FindConflicts, the start and end stations of a train hold any number of trains
+			if occupation.Station != path[0] && occupation.Station != path[len(path)-1] {
+				if occupied[occupation.Time] == nil {
+					occupied[occupation.Time] = make(map[string]int)
+				}
+				occupied[occupation.Time][occupation.Station]++
+			}
+
+			if k == 0 || occupations[k-1].Station != occupation.Station {
+				continue
+			}
+
+			// The train waited during this turn; find where it goes afterwards
+			next := leftFor[k]
+			if next == "" {
+				continue
+			}
+			if waiting[occupation.Time] == nil {
+				waiting[occupation.Time] = make(map[string][]waiter)
+			}
+			waiting[occupation.Time][occupation.Station] = append(waiting[occupation.Time][occupation.Station], waiter{trainID, next})
+		}
+	}
+
+	// blocked reports whether a train cannot enter a station at the end of the turn
+	blocked := func(turn int, trainID int, station string) bool {
+		if station == paths[trainID][len(paths[trainID])-1] {
+			return false // The end station of a train always takes it
+		}
+		s, exists := stations[station]
+		return exists && occupied[turn][station] >= s.Capacity()
+	}
+
+	// Each pair of trains is reported once, in the first turn they block each other
+	type pair struct {
+		trainID, opposingID int
+	}
+	first := make(map[pair]model.Conflict)
+	for turn, stationWaiters := range waiting {
+		for station, waiters := range stationWaiters {
+			for _, w := range waiters {
+				from, exists := stations[station]
+				if !exists || !IsConnected(from, w.next) || from.TrackTo(w.next).Capacity != 1 {
+					continue
+				}
+				for _, opposing := range stationWaiters[w.next] {
+					if opposing.next != station || opposing.trainID < w.trainID {
+						continue
+					}
+					if !blocked(turn, w.trainID, w.next) || !blocked(turn, opposing.trainID, station) {
+						continue
+					}
+
+					key := pair{w.trainID, opposing.trainID}
+					if conflict, seen := first[key]; seen && conflict.Turn <= turn {
+						continue
+					}
+					first[key] = model.Conflict{
+						Turn:    turn,
+						TrainID: w.trainID,
+						Message: fmt.Sprintf("deadlock with T%d: trains at %s and %s wait for each other on single track %s-%s",
+							opposing.trainID+1, station, w.next, station, w.next),
+					}
+				}
+			}
+		}
+	}
+
+	deadlocks := make([]model.Conflict, 0, len(first))
+	for _, conflict := range first {
+		deadlocks = append(deadlocks, conflict)
+	}
+	sortConflicts(deadlocks)
+	return deadlocks
+}
@@ -1,56 +1,172 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"station/internal/model"
 	"station/internal/utils"
+	"strings"
 )
 
 // FindAppropriateMap selects the most appropriate map based on the start and end stations
 // Parameters:
 //
-//	networks: A map of network names to their corresponding station maps
+//	networks: All networks of the map file, in file order
 //	start: The name of the starting station
 //	end: The name of the destination station
 //
 // Returns:
 //
-//	string: The name of the appropriate network
+//	string: The name of the appropriate network, the first one in file order if several contain both stations
 //	map[string]*data.Station: The selected network's station map
 //	error: An error if no appropriate map is found
-func FindAppropriateMap(networks map[string]map[string]*model.Station, start, end string) (string, map[string]*model.Station, error) {
-	startExists := false
-	endExists := false
-
-	// Iterate through all available networks to check if the start and end stations exist
-	for _, network := range networks {
-		if _, exists := network[start]; exists {
-			startExists = true
+func FindAppropriateMap(networks *model.Map, start, end string) (string, map[string]*model.Station, error) {
+	return FindMapForJobs(networks, []model.Job{{Start: start, End: end}})
+}
+
+// FindMapForJobs selects the network that contains the start and end stations of every job
+// Parameters:
+//
+//	networks: All networks of the map file, in file order
+//	jobs: The groups of trains that must share the network
+//
+// Returns:
+//
+//	string: The name of the appropriate network, the first one in file order if several hold all the jobs.
+//	        When no single network does, networks linked by interchanges are combined (see CombineNetworks).
+//	map[string]*model.Station: The selected network's station map
+//	error: An error if no network holds all the jobs
+func FindMapForJobs(networks *model.Map, jobs []model.Job) (string, map[string]*model.Station, error) {
+	// Each job on its own must be possible, which also yields the most precise error
+	for _, job := range jobs {
+		if err := checkStationsExist(networks, job.Start, job.End); err != nil {
+			return "", nil, err
 		}
-		if _, exists := network[end]; exists {
-			endExists = true
+	}
+
+	candidates := FindNetworks(networks, jobs)
+	if len(candidates) > 0 {
+		return candidates[0], networks.Networks[candidates[0]], nil
+	}
+
+	// Trains may still reach stations of other networks through interchanges
+	combined := CombineNetworks(networks)
+	for _, name := range combined.Order {
+		if containsJobs(combined.Networks[name], ResolveJobs(combined.Networks[name], jobs)) {
+			return name, combined.Networks[name], nil
 		}
 	}
 
+	// The stations are spread over different networks
+	if len(jobs) == 1 {
+		return "", nil, &utils.ConnectionError{Kind: utils.ErrNoPath, From: jobs[0].Start, To: jobs[0].End, Detail: "the stations are in different networks"}
+	}
+	return "", nil, fmt.Errorf("%w: the jobs are in different networks", utils.ErrNoPath)
+}
+
+// FindNetworks returns the names of all networks that contain the start and end stations of every job,
+// in file order. More than one name means the choice of FindMapForJobs is ambiguous.
+func FindNetworks(networks *model.Map, jobs []model.Job) []string {
+	var candidates []string
+	for _, name := range networks.Order {
+		if containsJobs(networks.Networks[name], jobs) {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
+
+// containsJobs reports whether a network contains the start and end stations of every job
+func containsJobs(network map[string]*model.Station, jobs []model.Job) bool {
+	for _, job := range jobs {
+		_, startExists := network[job.Start]
+		_, endExists := network[job.End]
+		if !startExists || !endExists {
+			return false
+		}
+	}
+	return true
+}
+
+// FindNamedMap returns the network with the given name, checking that it contains the stations of every job
+// Parameters:
+//
+//	networks: All networks of the map file
+//	name: The name of the network, as given in its "--- name ---" header
+//	jobs: The groups of trains that must run on the network
+//
+// Returns:
+//
+//	map[string]*model.Station: The network's station map
+//	error: An error if the network does not exist or lacks a start or end station
+func FindNamedMap(networks *model.Map, name string, jobs []model.Job) (map[string]*model.Station, error) {
+	network, exists := networks.Networks[name]
+	if !exists {
+		return nil, &utils.ValueError{Kind: utils.ErrNetworkNotExist, Value: name}
+	}
+	for _, job := range jobs {
+		if _, exists := network[job.Start]; !exists {
+			return nil, &utils.StationError{Kind: utils.ErrStartStationNotExist, Station: job.Start, Detail: "in network " + name}
+		}
+		if _, exists := network[job.End]; !exists {
+			return nil, &utils.StationError{Kind: utils.ErrEndStationNotExist, Station: job.End, Detail: "in network " + name}
+		}
+	}
+	return network, nil
+}
+
+// checkStationsExist checks that the start and end stations exist in at least one network
+func checkStationsExist(networks *model.Map, start, end string) error {
+	startExists := stationExists(networks, start)
+	endExists := stationExists(networks, end)
+
 	// If start station does not exist in any network
 	if !startExists {
-		return "", nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrStartStationNotExist, utils.Reset)
+		return &utils.StationError{Kind: utils.ErrStartStationNotExist, Station: start}
 	}
 
 	// If end station does not exist in any network
 	if !endExists {
-		return "", nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrEndStationNotExist, utils.Reset)
+		return &utils.StationError{Kind: utils.ErrEndStationNotExist, Station: end}
 	}
+	return nil
+}
 
-	// Iterate through all available networks to find one containing both the start and end stations
-	for name, network := range networks {
-		if _, startExists := network[start]; startExists {
-			if _, endExists := network[end]; endExists {
-				return name, network, nil
-			}
+// stationExists reports whether a station exists in any network, either by its own name or by
+// its name qualified with the identifier of its network, e.g. "london.st_pancras"
+func stationExists(networks *model.Map, name string) bool {
+	// Iterate through all available networks to check if the station exists
+	for _, network := range networks.Networks {
+		if _, exists := network[name]; exists {
+			return true
 		}
 	}
 
-	// If no suitable network is found
-	return "", nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrNoPath, utils.Reset)
+	id, station, found := strings.Cut(name, ".")
+	if !found {
+		return false
+	}
+	network, exists := networks.NetworkByID(id)
+	if !exists {
+		return false
+	}
+	_, exists = networks.Networks[network][station]
+	return exists
+}
+
+// ExplainMissingStation reports a map that could not be read because a connection refers to the
+// start or end station as that station missing, which is usually the mistake, rather than as the
+// broken connection. Any other error is returned unchanged.
+func ExplainMissingStation(err error, start, end string) error {
+	var missing *utils.StationError
+	if !errors.As(err, &missing) || !errors.Is(err, utils.ErrStationDoesNotExistInConnections) {
+		return err
+	}
+	switch missing.Station {
+	case start:
+		return &utils.StationError{Kind: utils.ErrStartStationNotExist, Station: start}
+	case end:
+		return &utils.StationError{Kind: utils.ErrEndStationNotExist, Station: end}
+	}
+	return err
 }
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"station/internal/model"
+	"strings"
+)
+
+// CombineNetworks joins the networks linked by interchanges, so that trains can be routed across them
+// Parameters:
+//
+//	networks: All networks of the map file, in file order, with their interchanges
+//
+// Returns:
+//
+//	A map with one combined network per group of networks linked by interchanges, in the file order of
+//	the first network of each group. A combined network is named after its networks joined with " + ",
+//	e.g. "London + Paris", and its stations are named after their network, e.g. "london.st_pancras",
+//	so that the output shows where trains change networks. Networks without interchanges are left out.
+func CombineNetworks(networks *model.Map) *model.Map {
+	combined := model.NewMap()
+	if len(networks.Interchanges) == 0 {
+		return combined
+	}
+
+	// Group the networks with a union-find over the interchanges
+	parent := make(map[string]string)
+	var find func(name string) string
+	find = func(name string) string {
+		if parent[name] == "" || parent[name] == name {
+			return name
+		}
+		parent[name] = find(parent[name])
+		return parent[name]
+	}
+	for _, interchange := range networks.Interchanges {
+		root1, root2 := find(interchange.Network1), find(interchange.Network2)
+		if root1 != root2 {
+			parent[root2] = root1
+		}
+	}
+
+	var roots []string
+	groups := make(map[string][]string)
+	for _, name := range networks.Order {
+		if !isLinked(networks, name) {
+			continue
+		}
+		root := find(name)
+		if groups[root] == nil {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], name)
+	}
+
+	for _, root := range roots {
+		group := groups[root]
+		stations := combined.AddNetwork(strings.Join(group, " + "))
+
+		// Networks are placed side by side, so that their drawings do not overlap
+		offset := 0
+		for _, name := range group {
+			width := 0
+			for _, station := range networks.Networks[name] {
+				clone := *station
+				clone.Name = model.QualifiedName(name, station.Name)
+				clone.X += offset
+				clone.Connections, clone.Tracks = nil, nil
+				stations[clone.Name] = &clone
+				width = max(width, station.X+1)
+			}
+			offset += width + 1
+		}
+
+		for _, name := range group {
+			for _, station := range networks.Networks[name] {
+				from := stations[model.QualifiedName(name, station.Name)]
+				for _, conn := range station.Connections {
+					to := stations[model.QualifiedName(name, conn.Name)]
+					from.Connections = append(from.Connections, to)
+					if track, ok := station.Tracks[conn.Name]; ok {
+						setTrack(from, to.Name, track)
+					}
+				}
+			}
+		}
+
+		for _, interchange := range networks.Interchanges {
+			if find(interchange.Network1) != root {
+				continue
+			}
+			from := stations[model.QualifiedName(interchange.Network1, interchange.Station1)]
+			to := stations[model.QualifiedName(interchange.Network2, interchange.Station2)]
+			from.Connections = append(from.Connections, to)
+			to.Connections = append(to.Connections, from)
+			setTrack(from, to.Name, interchange.Track)
+			setTrack(to, from.Name, interchange.Track)
+		}
+	}
+
+	combined.Interchanges = networks.Interchanges
+	return combined
+}
+
+// isLinked reports whether an interchange leads to or from the named network
+func isLinked(networks *model.Map, name string) bool {
+	for _, interchange := range networks.Interchanges {
+		if interchange.Network1 == name || interchange.Network2 == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setTrack records the track leading from a station to the named station
+func setTrack(station *model.Station, to string, track *model.Track) {
+	if station.Tracks == nil {
+		station.Tracks = make(map[string]*model.Track)
+	}
+	station.Tracks[to] = track
+}
+
+// ResolveStation returns the name of a station in a network. Stations of combined networks are named
+// after their network (see CombineNetworks), but may still be given by their own name as long as only
+// one network of the group has a station of that name. Any other name is returned unchanged.
+func ResolveStation(stations map[string]*model.Station, name string) string {
+	if _, exists := stations[name]; exists {
+		return name
+	}
+	resolved := name
+	matches := 0
+	for qualified := range stations {
+		if strings.HasSuffix(qualified, "."+name) {
+			resolved = qualified
+			matches++
+		}
+	}
+	if matches != 1 {
+		return name
+	}
+	return resolved
+}
+
+// ResolveJobs returns the jobs with their start and end stations resolved by ResolveStation
+func ResolveJobs(stations map[string]*model.Station, jobs []model.Job) []model.Job {
+	resolved := make([]model.Job, len(jobs))
+	for i, job := range jobs {
+		resolved[i] = model.Job{Start: ResolveStation(stations, job.Start), End: ResolveStation(stations, job.End), Trains: job.Trains}
+	}
+	return resolved
+}
@@ -4,28 +4,81 @@ import (
 	"station/internal/model"
 )
 
-// createOccupations generates occupation information for a given path and train
+// CreateMovements turns a train's path into timed movements
+// Parameters:
+//
+//	path: A slice of strings representing the sequence of stations in the train's path,
+//	      where a repeated station means the train waits there for one turn
+//	trainID: An integer identifier for the train
+//	stations: A map of all stations in the network, used to look up track durations
+//
+// Returns:
+//
+//	A slice of model.Movement structs, one per step of the path, starting at turn 1
+func CreateMovements(path []string, trainID int, stations map[string]*model.Station) []model.Movement {
+	movements := make([]model.Movement, 0, len(path))
+	turn := 0
+
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+
+		// Waiting takes a single turn, travelling takes as long as the track requires
+		duration := 1
+		if from != to {
+			if station, exists := stations[from]; exists {
+				duration = station.TrackTo(to).Duration
+			}
+		}
+
+		movements = append(movements, model.Movement{
+			TrainID: trainID,
+			From:    from,
+			To:      to,
+			Depart:  turn + 1,
+			Arrive:  turn + duration,
+		})
+		turn += duration
+	}
+
+	return movements
+}
+
+// TravelTime returns the number of turns a train needs to follow the given path
+func TravelTime(path []string, stations map[string]*model.Station) int {
+	movements := CreateMovements(path, 0, stations)
+	if len(movements) == 0 {
+		return 0
+	}
+	return movements[len(movements)-1].Arrive
+}
+
+// CreateOccupations generates occupation information for a given path and train
 // Parameters:
 //
 //	path: A slice of strings representing the sequence of stations in the train's path
 //	trainID: An integer identifier for the train
+//	stations: A map of all stations in the network, used to look up track durations
 //
 // Returns:
 //
-//	A slice of data.OccupationInfo structs representing the occupation of each station at each time step
-func CreateOccupations(path []string, trainID int) []model.OccupationInfo {
+//	A slice of model.OccupationInfo structs representing the occupation of each station at each time step.
+//	Turns spent travelling between stations do not occupy any station.
+func CreateOccupations(path []string, trainID int, stations map[string]*model.Station) []model.OccupationInfo {
 	// Initialize a slice to store occupation information
-	// The length of this slice will be equal to the number of stations in the path
-	occupations := make([]model.OccupationInfo, len(path))
-
-	// Iterate through each station in the path
-	for i, station := range path {
-		// Create an OccupationInfo struct for each station
-		occupations[i] = model.OccupationInfo{
-			Station: station, // The name of the current station
-			Time:    i,       // The time step (assumed to be the index in the path)
-			TrainID: trainID, // The ID of the train occupying this station
-		}
+	// The train stands at its first station before the first turn
+	occupations := make([]model.OccupationInfo, 0, len(path))
+	if len(path) == 0 {
+		return occupations
+	}
+	occupations = append(occupations, model.OccupationInfo{Station: path[0], Time: 0, TrainID: trainID})
+
+	// Record the station reached at the end of every movement
+	for _, movement := range CreateMovements(path, trainID, stations) {
+		occupations = append(occupations, model.OccupationInfo{
+			Station: movement.To,     // The name of the current station
+			Time:    movement.Arrive, // The turn in which the train reaches the station
+			TrainID: trainID,         // The ID of the train occupying this station
+		})
 	}
 
 	// Return the completed slice of occupation information
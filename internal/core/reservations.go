@@ -0,0 +1,120 @@
+package core
+
+import (
+	"station/internal/model"
+)
+
+// Reservations records which stations and tracks planned trains use during each turn,
+// so that further trains can be planned around them following the rules of FindConflicts
+type Reservations struct {
+	stations map[string]*model.Station // The network the trains run on
+	occupied map[string]map[int]int    // Trains standing at a station, keyed by station and turn
+	entering map[[2]string]map[int]int // Trains entering a track, keyed by undirected track and turn
+	onTrack  map[[2]string]map[int]int // Trains on a track, keyed by direction of travel and turn
+	closures []model.Closure           // Stations and connections out of service
+	lastTurn int                       // The last turn in which any resource is reserved
+}
+
+// NewReservations creates an empty reservation table for the given network
+func NewReservations(stations map[string]*model.Station) *Reservations {
+	return &Reservations{
+		stations: stations,
+		occupied: make(map[string]map[int]int),
+		entering: make(map[[2]string]map[int]int),
+		onTrack:  make(map[[2]string]map[int]int),
+	}
+}
+
+// Reserve books the stations and tracks used by a train following the given path.
+// Like in FindConflicts, the train's own start and end stations are not booked.
+func (r *Reservations) Reserve(path []string) {
+	for _, movement := range CreateMovements(path, 0, r.stations) {
+		if movement.From != movement.To {
+			increment(r.entering, trackKey(movement.From, movement.To), movement.Depart)
+			for turn := movement.Depart; turn <= movement.Arrive; turn++ {
+				increment(r.onTrack, [2]string{movement.From, movement.To}, turn)
+			}
+		}
+		if movement.To != path[0] && movement.To != path[len(path)-1] {
+			increment(r.occupied, movement.To, movement.Arrive)
+		}
+		if movement.Arrive > r.lastTurn {
+			r.lastTurn = movement.Arrive
+		}
+	}
+}
+
+// Close takes stations and connections out of service, so that no train is planned along their tracks
+// while they are closed. The turns of the closures count as reserved for LastTurn.
+func (r *Reservations) Close(closures []model.Closure) {
+	r.closures = append(r.closures, closures...)
+	for _, closure := range closures {
+		if closure.End > r.lastTurn {
+			r.lastTurn = closure.End
+		}
+	}
+}
+
+// LastTurn returns the last turn in which any station or track is reserved
+func (r *Reservations) LastTurn() int {
+	return r.lastTurn
+}
+
+// CanStand reports whether a platform of the station is free at the end of the given turn
+func (r *Reservations) CanStand(station string, turn int) bool {
+	platforms := 1
+	if s, exists := r.stations[station]; exists {
+		platforms = s.Capacity()
+	}
+	return r.occupied[station][turn] < platforms
+}
+
+// CanTravel reports whether a train may enter the track from one station to another in the given turn.
+// The track must have spare capacity in that turn and stay open for the whole journey, and a single
+// track must be free of opposing trains for the whole journey. Whether the destination has a free
+// platform is checked with CanStand.
+func (r *Reservations) CanTravel(from, to string, depart int) bool {
+	track := r.stations[from].TrackTo(to)
+	if r.entering[trackKey(from, to)][depart] >= track.Capacity {
+		return false
+	}
+	if _, _, closed := findClosure(r.closures, from, to, depart, depart+track.Duration-1); closed {
+		return false
+	}
+
+	if track.Capacity == 1 {
+		for turn := depart; turn < depart+track.Duration; turn++ {
+			if r.onTrack[[2]string{to, from}][turn] > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ClosedUntil returns the last turn of a closure that keeps a train from entering the track from one
+// station to another in the given turn, or 0 if no closure does. Departing after that turn avoids the
+// closure, although another one may follow.
+func (r *Reservations) ClosedUntil(from, to string, depart int) int {
+	track := r.stations[from].TrackTo(to)
+	if closure, _, closed := findClosure(r.closures, from, to, depart, depart+track.Duration-1); closed {
+		return closure.End
+	}
+	return 0
+}
+
+// trackKey returns the same key for both directions of a track
+func trackKey(from, to string) [2]string {
+	if from > to {
+		return [2]string{to, from}
+	}
+	return [2]string{from, to}
+}
+
+// increment adds one train to the count of the given key and turn
+func increment[K comparable](counts map[K]map[int]int, key K, turn int) {
+	if counts[key] == nil {
+		counts[key] = make(map[int]int)
+	}
+	counts[key][turn]++
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"sort"
+	"station/internal/model"
+	"station/internal/utils"
+)
+
+// dfsTree is a depth-first search tree of the network, as used by Tarjan's algorithm
+type dfsTree struct {
+	stations map[string]*model.Station
+	order    map[string]int      // Discovery order of every visited station
+	low      map[string]int      // Lowest discovery order reachable from the subtree through one back edge
+	last     map[string]int      // Highest discovery order within the subtree of every station
+	children map[string][]string // Stations first reached from every station, in visiting order
+	next     int                 // Discovery order of the next station to visit
+}
+
+// FindSinglePoints finds the articulation points and bridges of a network with Tarjan's algorithm
+// Parameters:
+//
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	Every station and every connection whose loss splits its part of the network in two. A station
+//	at the end of a bridge is an articulation point too, unless the bridge is its only connection.
+func FindSinglePoints(stations map[string]*model.Station) model.Resilience {
+	tree := newDFSTree(stations)
+	var resilience model.Resilience
+
+	for _, root := range sortedNames(stations) {
+		if _, visited := tree.order[root]; visited {
+			continue
+		}
+		tree.visit(root, "")
+
+		// A root is an articulation point exactly when it has several children
+		if len(tree.children[root]) > 1 {
+			resilience.ArticulationPoints = append(resilience.ArticulationPoints, root)
+		}
+		tree.collect(root, &resilience, func(string) bool { return true })
+	}
+
+	sortResilience(&resilience)
+	return resilience
+}
+
+// FindSeparatingPoints finds the articulation points and bridges that cut off one station from another
+// Parameters:
+//
+//	stations: A map of all stations in the network, keyed by station name
+//	start: The name of the starting station
+//	end: The name of the destination station
+//
+// Returns:
+//
+//	Every station other than start and end, and every connection, that all routes from start to end
+//	pass through, or an error if a station does not exist or no route connects them
+func FindSeparatingPoints(stations map[string]*model.Station, start, end string) (model.Resilience, error) {
+	if _, exists := stations[start]; !exists {
+		return model.Resilience{}, &utils.StationError{Kind: utils.ErrStartStationNotExist, Station: start}
+	}
+	if _, exists := stations[end]; !exists {
+		return model.Resilience{}, &utils.StationError{Kind: utils.ErrEndStationNotExist, Station: end}
+	}
+	if start == end {
+		return model.Resilience{}, &utils.StationError{Kind: utils.ErrSameStartEndStation, Station: start}
+	}
+
+	// Root the tree at the start station; the end station then lies in the subtree cut off by
+	// every separating station and connection, while the end station's own subtrees never hold it
+	tree := newDFSTree(stations)
+	tree.visit(start, "")
+	if _, reached := tree.order[end]; !reached {
+		return model.Resilience{}, &utils.ConnectionError{Kind: utils.ErrNoPath, From: start, To: end}
+	}
+
+	var resilience model.Resilience
+	tree.collect(start, &resilience, func(child string) bool {
+		return tree.order[child] <= tree.order[end] && tree.order[end] <= tree.last[child]
+	})
+
+	sortResilience(&resilience)
+	return resilience, nil
+}
+
+// newDFSTree creates an empty search tree over the network
+func newDFSTree(stations map[string]*model.Station) *dfsTree {
+	return &dfsTree{
+		stations: stations,
+		order:    make(map[string]int),
+		low:      make(map[string]int),
+		last:     make(map[string]int),
+		children: make(map[string][]string),
+	}
+}
+
+// visit searches the network depth first from a station reached from parent, recording discovery
+// orders and low links. Connections are visited in alphabetical order so that reports are deterministic.
+func (t *dfsTree) visit(name, parent string) {
+	t.order[name], t.low[name] = t.next, t.next
+	t.next++
+
+	for _, conn := range sortedConnections(t.stations[name]) {
+		if conn == parent {
+			continue // Connections are unique, so the track back to the parent is no cycle
+		}
+		if _, visited := t.order[conn]; visited {
+			t.low[name] = min(t.low[name], t.order[conn])
+			continue
+		}
+		t.children[name] = append(t.children[name], conn)
+		t.visit(conn, name)
+		t.low[name] = min(t.low[name], t.low[conn])
+	}
+	t.last[name] = t.next - 1
+}
+
+// collect adds the articulation points and bridges below a station of the tree whose cut-off
+// subtree is accepted by the filter. Roots are left to the caller, since their rule differs.
+func (t *dfsTree) collect(root string, resilience *model.Resilience, accept func(child string) bool) {
+	stack := []string{root}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		articulation := false
+		for _, child := range t.children[name] {
+			stack = append(stack, child)
+			if !accept(child) {
+				continue
+			}
+			if t.low[child] > t.order[name] {
+				resilience.Bridges = append(resilience.Bridges, orderedPair(name, child))
+			}
+			if name != root && t.low[child] >= t.order[name] {
+				articulation = true
+			}
+		}
+		if articulation {
+			resilience.ArticulationPoints = append(resilience.ArticulationPoints, name)
+		}
+	}
+}
+
+// sortedNames returns the names of all stations in alphabetical order
+func sortedNames(stations map[string]*model.Station) []string {
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedConnections returns the names of the stations connected to a station in alphabetical order
+func sortedConnections(station *model.Station) []string {
+	names := make([]string, len(station.Connections))
+	for i, conn := range station.Connections {
+		names[i] = conn.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orderedPair returns the names of the stations of a connection in alphabetical order
+func orderedPair(name1, name2 string) [2]string {
+	if name2 < name1 {
+		return [2]string{name2, name1}
+	}
+	return [2]string{name1, name2}
+}
+
+// sortResilience orders the stations and connections of a report alphabetically
+func sortResilience(resilience *model.Resilience) {
+	sort.Strings(resilience.ArticulationPoints)
+	sort.Slice(resilience.Bridges, func(i, j int) bool {
+		if resilience.Bridges[i][0] != resilience.Bridges[j][0] {
+			return resilience.Bridges[i][0] < resilience.Bridges[j][0]
+		}
+		return resilience.Bridges[i][1] < resilience.Bridges[j][1]
+	})
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"fmt"
+	"station/internal/model"
+)
+
+// ValidateLog checks a movement log, e.g. a hand-edited simulation output, against the rules of the network
+// Parameters:
+//
+//	entries: The train positions of the log, as returned by io.ReadMovementLog
+//	start, end: The names of the start and end stations of every train
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	The number of turns in the log, and the violations ordered by turn, empty if the log is valid.
+//	Besides the rules checked by FindConflicts, trains must take as long as the tracks require,
+//	appear at most once per turn, and every train must reach the end station.
+func ValidateLog(entries []model.LogEntry, start, end string, stations map[string]*model.Station) (int, []model.Conflict) {
+	turns, numTrains := 0, 0
+	for _, entry := range entries {
+		if entry.Turn > turns {
+			turns = entry.Turn
+		}
+		if entry.TrainID+1 > numTrains {
+			numTrains = entry.TrainID + 1
+		}
+	}
+
+	var violations []model.Conflict
+	violation := func(turn, trainID int, format string, args ...interface{}) {
+		violations = append(violations, model.Conflict{Turn: turn, TrainID: trainID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Rebuild the path of every train, inserting waits wherever the log shows no movement
+	paths := make([][]string, numTrains)
+	arrived := make([]int, numTrains)                // Turn in which each train reached its last station
+	inTransit := make([][]model.LogEntry, numTrains) // Transit positions not yet followed by an arrival
+	seen := make(map[[2]int]bool)                    // Trains already listed in a turn
+
+	for i := range paths {
+		paths[i] = []string{start}
+	}
+
+	for _, entry := range entries {
+		trainID := entry.TrainID
+		if seen[[2]int{entry.Turn, trainID}] {
+			violation(entry.Turn, trainID, "listed more than once in the same turn")
+			continue
+		}
+		seen[[2]int{entry.Turn, trainID}] = true
+
+		current := paths[trainID][len(paths[trainID])-1]
+		if current == end {
+			violation(entry.Turn, trainID, "moves after reaching end station %s", end)
+			continue
+		}
+
+		// Unconnected stations are reported by FindConflicts, a single turn is assumed for them
+		duration := 1
+		if station, exists := stations[current]; exists && IsConnected(station, entry.Station) {
+			duration = station.TrackTo(entry.Station).Duration
+		}
+
+		if entry.Duration > 0 {
+			inTransit[trainID] = append(inTransit[trainID], entry)
+			continue
+		}
+
+		waits := entry.Turn - duration - arrived[trainID]
+		if waits < 0 {
+			violation(entry.Turn, trainID, "reaches %s after %d turn(s), the track from %s takes %d", entry.Station, entry.Turn-arrived[trainID], current, duration)
+			waits = 0
+		}
+
+		// Transit positions must lead to this station and match the time spent on the track
+		for _, transit := range inTransit[trainID] {
+			progress := transit.Turn - (entry.Turn - duration)
+			if transit.Station != entry.Station || transit.Duration != duration || transit.Progress != progress {
+				violation(transit.Turn, trainID, "shown as %s(%d/%d), expected %s(%d/%d)", transit.Station, transit.Progress, transit.Duration, entry.Station, progress, duration)
+			}
+		}
+		inTransit[trainID] = nil
+
+		for i := 0; i < waits; i++ {
+			paths[trainID] = append(paths[trainID], current) // Train waits at its station
+		}
+		paths[trainID] = append(paths[trainID], entry.Station)
+		arrived[trainID] = entry.Turn
+	}
+
+	for trainID, path := range paths {
+		if last := path[len(path)-1]; last != end {
+			violation(turns, trainID, "does not reach end station %s, last seen at %s", end, last)
+		}
+	}
+
+	// Add the network rules, skipping conflicts already reported above
+	reported := make(map[model.Conflict]bool)
+	for _, v := range violations {
+		reported[v] = true
+	}
+	for _, conflict := range FindConflicts(paths, stations) {
+		if !reported[conflict] {
+			reported[conflict] = true
+			violations = append(violations, conflict)
+		}
+	}
+
+	sortConflicts(violations)
+	return turns, violations
+}
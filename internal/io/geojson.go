@@ -0,0 +1,391 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	goio "io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"station/internal/model"
+	"station/internal/utils"
+	"strings"
+)
+
+// geoJSONCollection is a GeoJSON FeatureCollection of stations and connections
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name,omitempty"`   // Network name used for features without a network property
+	Scale    float64          `json:"scale,omitempty"`  // Grid units per coordinate unit, see newGridProjection
+	Origin   []float64        `json:"origin,omitempty"` // Coordinates placed at grid position 0,0, see newGridProjection
+	Features []geoJSONFeature `json:"features"`
+}
+
+// projectedGridSize is the number of grid units the longer side of the bounding box of the stations
+// spans when geographic coordinates are projected without a scale
+const projectedGridSize = 100
+
+// gridProjection maps GeoJSON coordinates onto the integer grid of map files
+type gridProjection struct {
+	originX, originY float64 // Coordinates placed at grid position 0,0
+	scale            float64 // Grid units per coordinate unit
+}
+
+// geoJSONFeature is a station (Point) or a connection (LineString)
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONGeometry   `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// geoJSONGeometry holds the coordinates of a Point ([x, y]) or a LineString ([[x, y], ...])
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// geoJSONProperties are the properties of stations and connections
+type geoJSONProperties struct {
+	Network     string `json:"network,omitempty"`     // Network the feature belongs to
+	Name        string `json:"name,omitempty"`        // Station name, for Point features
+	Platforms   int    `json:"platforms,omitempty"`   // Station platforms, for Point features
+	From        string `json:"from,omitempty"`        // First station, for LineString features
+	To          string `json:"to,omitempty"`          // Second station, for LineString features
+	Duration    int    `json:"duration,omitempty"`    // Track duration, for LineString features
+	Capacity    int    `json:"capacity,omitempty"`    // Track capacity, for LineString features
+	Interchange bool   `json:"interchange,omitempty"` // Whether a LineString links stations of two networks
+}
+
+// isGeoJSON reports whether a map file should be read as GeoJSON, based on its extension
+func isGeoJSON(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".geojson" || ext == ".json"
+}
+
+// ReadGeoJSON reads networks from a GeoJSON FeatureCollection.
+// Point features with a "name" property become stations and LineString features become connections,
+// either between the stations named by their "from" and "to" properties or between the stations at
+// their first and last coordinates. LineString features with a true "interchange" property become
+// interchanges between the stations named "network.station" by their "from" and "to" properties. Features are grouped into networks by their "network" property,
+// falling back to the collection's "name" and then to the file name. Coordinates other than
+// non-negative integers, such as WGS84 longitudes and latitudes, are projected onto the grid (see
+// newGridProjection). Stations and connections are validated by the same rules as in .map files,
+// on the projected coordinates.
+func ReadGeoJSON(path string) (*model.Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	defaultNetwork := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseGeoJSON(file, defaultNetwork)
+}
+
+// ParseGeoJSON decodes a FeatureCollection from a reader, adding all stations before any connection.
+// Features without a network property, in a collection without a name, belong to defaultNetwork.
+func ParseGeoJSON(r goio.Reader, defaultNetwork string) (*model.Map, error) {
+	var collection geoJSONCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrInvalidGeoJSON, err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%w: expected a FeatureCollection, got '%s'", utils.ErrInvalidGeoJSON, collection.Type)
+	}
+	if collection.Name != "" {
+		defaultNetwork = collection.Name
+	}
+
+	allNetworks := model.NewMap()
+	networkOf := func(feature geoJSONFeature) (string, map[string]*model.Station) {
+		name := feature.Properties.Network
+		if name == "" {
+			name = defaultNetwork
+		}
+		return name, allNetworks.AddNetwork(name)
+	}
+
+	// Decode the positions of all stations first, as the projection depends on all of them
+	points := make(map[int][]float64)
+	for i, feature := range collection.Features {
+		if feature.Geometry.Type != "Point" {
+			continue
+		}
+		var coordinates []float64
+		if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil || len(coordinates) < 2 {
+			return nil, fmt.Errorf("%w: feature %d: a Point needs [x, y] coordinates", utils.ErrInvalidGeoJSON, i)
+		}
+		points[i] = coordinates
+	}
+	projection, err := newGridProjection(collection, points)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stations first, so that connections may come in any order
+	for i, feature := range collection.Features {
+		if feature.Geometry.Type != "Point" {
+			continue
+		}
+		x, errX := projection.coordinate(points[i][0], projection.originX, "x", feature.Properties.Name)
+		if errX != nil {
+			return nil, errX
+		}
+		y, errY := projection.coordinate(points[i][1], projection.originY, "y", feature.Properties.Name)
+		if errY != nil {
+			return nil, errY
+		}
+
+		// Stations without a platform count hold a single train
+		platforms := feature.Properties.Platforms
+		if platforms == 0 {
+			platforms = 1
+		}
+
+		network, stations := networkOf(feature)
+		if len(stations) >= maxStations {
+			return nil, &utils.LineError{Network: network, Err: utils.ErrTooManyStations}
+		}
+		if err := addStation(feature.Properties.Name, x, y, platforms, stations); err != nil {
+			return nil, &utils.LineError{Network: network, Err: err}
+		}
+	}
+
+	var interchanges []geoJSONFeature
+	for i, feature := range collection.Features {
+		switch feature.Geometry.Type {
+		case "Point":
+			continue
+		case "LineString":
+		default:
+			return nil, fmt.Errorf("%w: feature %d: unsupported geometry '%s'", utils.ErrInvalidGeoJSON, i, feature.Geometry.Type)
+		}
+
+		// Interchanges may link networks defined by later features, so they are added at the end
+		if feature.Properties.Interchange {
+			if feature.Properties.From == "" || feature.Properties.To == "" {
+				return nil, fmt.Errorf("%w: feature %d: an interchange needs from and to properties", utils.ErrInvalidGeoJSON, i)
+			}
+			interchanges = append(interchanges, feature)
+			continue
+		}
+
+		network, stations := networkOf(feature)
+		from, to := feature.Properties.From, feature.Properties.To
+		if from == "" || to == "" {
+			var coordinates [][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil || len(coordinates) < 2 {
+				return nil, fmt.Errorf("%w: feature %d: a LineString needs at least two [x, y] positions", utils.ErrInvalidGeoJSON, i)
+			}
+			from = stationAt(stations, projection, coordinates[0])
+			to = stationAt(stations, projection, coordinates[len(coordinates)-1])
+		}
+
+		if err := checkConnection(from, to, stations); err != nil {
+			return nil, &utils.LineError{Network: network, Err: err}
+		}
+		track, err := featureTrack(feature, from, to)
+		if err != nil {
+			return nil, &utils.LineError{Network: network, Err: err}
+		}
+		linkStations(stations[from], stations[to], track)
+	}
+
+	for _, feature := range interchanges {
+		track, err := featureTrack(feature, feature.Properties.From, feature.Properties.To)
+		if err != nil {
+			return nil, err
+		}
+		if err := addInterchange(feature.Properties.From, feature.Properties.To, track, allNetworks); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(allNetworks.Order) == 0 {
+		return nil, utils.ErrNoNetwork
+	}
+	return allNetworks, nil
+}
+
+// featureTrack returns the track described by the duration and capacity properties of a LineString
+// feature between two stations, with the properties of model.DefaultTrack where they are missing
+func featureTrack(feature geoJSONFeature, from, to string) (*model.Track, error) {
+	track := model.DefaultTrack
+	if feature.Properties.Duration < 0 {
+		return nil, invalidTrack(from, to, "duration", fmt.Sprint(feature.Properties.Duration))
+	}
+	if feature.Properties.Capacity < 0 {
+		return nil, invalidTrack(from, to, "capacity", fmt.Sprint(feature.Properties.Capacity))
+	}
+	if feature.Properties.Duration > 0 {
+		track.Duration = feature.Properties.Duration
+	}
+	if feature.Properties.Capacity > 0 {
+		track.Capacity = feature.Properties.Capacity
+	}
+	return &track, nil
+}
+
+// newGridProjection chooses how the coordinates of a collection are placed on the grid
+// Parameters:
+//
+//	collection: The collection, whose optional "scale" and "origin" members fix the projection
+//	points: The coordinates of every Point feature, keyed by feature index
+//
+// Returns:
+//
+//	The identity when every coordinate already is a non-negative integer and neither member is given,
+//	as in files written by WriteGeoJSON. Otherwise coordinates are shifted by the origin, by default the
+//	lower left corner of the bounding box of the stations, multiplied by the scale, by default such that
+//	the longer side of the bounding box spans projectedGridSize units, and rounded to the nearest
+//	integer. Longitudes become x and latitudes y. An error is returned for a malformed scale or origin.
+func newGridProjection(collection geoJSONCollection, points map[int][]float64) (gridProjection, error) {
+	if collection.Scale < 0 || math.IsInf(collection.Scale, 0) || math.IsNaN(collection.Scale) {
+		return gridProjection{}, fmt.Errorf("%w: the scale must be a positive number, got %v", utils.ErrInvalidGeoJSON, collection.Scale)
+	}
+	if collection.Origin != nil && len(collection.Origin) != 2 {
+		return gridProjection{}, fmt.Errorf("%w: the origin must be an [x, y] position", utils.ErrInvalidGeoJSON)
+	}
+
+	onGrid := true
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, point := range points {
+		x, y := point[0], point[1]
+		if x < 0 || y < 0 || x != math.Trunc(x) || y != math.Trunc(y) {
+			onGrid = false
+		}
+		minX, minY, maxX, maxY = math.Min(minX, x), math.Min(minY, y), math.Max(maxX, x), math.Max(maxY, y)
+	}
+	if onGrid && collection.Scale == 0 && collection.Origin == nil {
+		return gridProjection{scale: 1}, nil
+	}
+
+	projection := gridProjection{originX: minX, originY: minY, scale: collection.Scale}
+	if collection.Origin != nil {
+		projection.originX, projection.originY = collection.Origin[0], collection.Origin[1]
+	}
+	if projection.scale == 0 {
+		projection.scale = 1
+		if extent := math.Max(maxX-minX, maxY-minY); extent > 0 {
+			projection.scale = projectedGridSize / extent
+		}
+	}
+	return projection, nil
+}
+
+// coordinate converts a GeoJSON coordinate into a map coordinate, which must be a non-negative integer
+func (p gridProjection) coordinate(value, origin float64, axis, name string) (int, error) {
+	projected := math.Round((value - origin) * p.scale)
+	if projected < 0 || math.IsNaN(projected) || projected > math.MaxInt32 {
+		return 0, invalidCoordinate(name, axis, fmt.Sprint(value))
+	}
+	return int(projected), nil
+}
+
+// stationAt returns the name of the station at the grid position the given position projects to,
+// or "" if there is none
+func stationAt(stations map[string]*model.Station, projection gridProjection, position []float64) string {
+	if len(position) < 2 {
+		return ""
+	}
+	x, errX := projection.coordinate(position[0], projection.originX, "x", "")
+	y, errY := projection.coordinate(position[1], projection.originY, "y", "")
+	if errX != nil || errY != nil {
+		return ""
+	}
+	for name, station := range stations {
+		if station.X == x && station.Y == y {
+			return name
+		}
+	}
+	return ""
+}
+
+// WriteGeoJSON writes networks as a single GeoJSON FeatureCollection that ReadGeoJSON reads back
+// Parameters:
+//
+//	w: The writer to write the collection to
+//	networks: All networks of a map file, as returned by ReadMapFile
+//
+// Returns:
+//
+//	Any error encountered while writing.
+//	Every station becomes a Point and every connection a LineString, both tagged with their network,
+//	and networks are written in file order. Interchanges follow as LineStrings marked as interchanges.
+func WriteGeoJSON(w goio.Writer, networks *model.Map) error {
+	collection := geoJSONCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	if len(networks.Order) == 1 {
+		collection.Name = networks.Order[0]
+	}
+
+	// Write stations and connections in a stable order
+	for _, network := range networks.Order {
+		stations := networks.Networks[network]
+		names := make([]string, 0, len(stations))
+		for name := range stations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			station := stations[name]
+			coordinates, _ := json.Marshal([]int{station.X, station.Y})
+			collection.Features = append(collection.Features, geoJSONFeature{
+				Type:       "Feature",
+				Geometry:   geoJSONGeometry{Type: "Point", Coordinates: coordinates},
+				Properties: geoJSONProperties{Network: network, Name: name, Platforms: station.Capacity()},
+			})
+		}
+
+		for _, name := range names {
+			station := stations[name]
+			connections := make([]string, 0, len(station.Connections))
+			for _, conn := range station.Connections {
+				if conn.Name > name {
+					connections = append(connections, conn.Name) // Written once, from the smaller name
+				}
+			}
+			sort.Strings(connections)
+
+			for _, conn := range connections {
+				other := stations[conn]
+				track := station.TrackTo(conn)
+				coordinates, _ := json.Marshal([][]int{{station.X, station.Y}, {other.X, other.Y}})
+				collection.Features = append(collection.Features, geoJSONFeature{
+					Type:     "Feature",
+					Geometry: geoJSONGeometry{Type: "LineString", Coordinates: coordinates},
+					Properties: geoJSONProperties{
+						Network:  network,
+						From:     name,
+						To:       conn,
+						Duration: track.Duration,
+						Capacity: track.Capacity,
+					},
+				})
+			}
+		}
+	}
+
+	// Interchanges name their stations with the network, so they need no network of their own
+	for _, interchange := range networks.Interchanges {
+		station1 := networks.Networks[interchange.Network1][interchange.Station1]
+		station2 := networks.Networks[interchange.Network2][interchange.Station2]
+		coordinates, _ := json.Marshal([][]int{{station1.X, station1.Y}, {station2.X, station2.Y}})
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "LineString", Coordinates: coordinates},
+			Properties: geoJSONProperties{
+				From:        model.NetworkID(interchange.Network1) + "." + interchange.Station1,
+				To:          model.NetworkID(interchange.Network2) + "." + interchange.Station2,
+				Duration:    interchange.Track.Duration,
+				Capacity:    interchange.Track.Capacity,
+				Interchange: true,
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
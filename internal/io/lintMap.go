@@ -0,0 +1,483 @@
+package io
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	goio "io"
+	"os"
+	"sort"
+	"station/internal/model"
+	"station/internal/utils"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Diagnostic codes reported by LintMap. The codes are stable, so that tools can filter on them.
+const (
+	CodeSyntax              = "E001" // A line that cannot be parsed
+	CodeInvalidName         = "E002" // A station name with characters other than a-z, 0-9 and _
+	CodeInvalidCoordinate   = "E003" // A coordinate that is not a non-negative integer
+	CodeDuplicateStation    = "E004" // A station name defined twice in a network
+	CodeSameCoordinates     = "E005" // Two stations at the same coordinates
+	CodeInvalidPlatforms    = "E006" // A platform count that is not a positive integer
+	CodeUnknownStation      = "E007" // A connection to a station that is not defined
+	CodeDuplicateConnection = "E008" // A connection defined twice, in either direction
+	CodeSelfConnection      = "E009" // A connection from a station to itself
+	CodeInvalidTrack        = "E010" // A track duration or capacity that is not a positive integer
+	CodeMissingSection      = "E011" // A network without a stations: or connections: section
+	CodeTooManyStations     = "E012" // A network with more than 10000 stations
+	CodeNoNetwork           = "E013" // A map without any network
+	CodeIsolatedStation     = "W001" // A station without connections
+	CodeUnreachable         = "W002" // Stations that cannot be reached from the rest of their network
+)
+
+// position is the place of a definition in the map file
+type position struct {
+	line, column int
+}
+
+// field is a piece of a line together with the column it starts at
+type field struct {
+	text   string
+	column int
+}
+
+// linter collects the diagnostics of a map file while building its networks from the valid lines
+type linter struct {
+	file        string
+	diagnostics []model.Diagnostic
+
+	networks    map[string]map[string]*model.Station
+	order       []string                       // Network names in file order
+	stations    map[string]map[string]position // Where each station is defined, per network
+	connections map[string]map[[2]string]int   // Line of each connection, per network
+	invalid     map[string]map[string]bool     // Stations whose definition was rejected, per network
+}
+
+// LintMap checks a map file and reports every problem found, instead of stopping at the first one
+// Parameters:
+//
+//	path: The path of the map file, or StdinPath to read standard input
+//
+// Returns:
+//
+//	The diagnostics ordered by line and column, and an error if the file cannot be read.
+//	Lines with errors are skipped and checking goes on with the next line. Once the file is parsed,
+//	stations without connections and parts of a network that cannot be reached are reported as warnings.
+func LintMap(path string) ([]model.Diagnostic, error) {
+	if path == StdinPath {
+		return LintReader(os.Stdin, "<stdin>")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LintReader(file, path)
+}
+
+// LintReader checks a map read from r like LintMap, reporting name as the file of every diagnostic
+func LintReader(r goio.Reader, name string) ([]model.Diagnostic, error) {
+	l := &linter{
+		file:        name,
+		networks:    make(map[string]map[string]*model.Station),
+		stations:    make(map[string]map[string]position),
+		connections: make(map[string]map[[2]string]int),
+		invalid:     make(map[string]map[string]bool),
+	}
+
+	var network string
+	var header int // Line of the current network header
+	inStations, inConnections, inInterchanges := false, false, false
+	hasStations, hasConnections := false, false
+	reportedOutside := false // Lines outside a network or a section are reported once per network
+	reportedSection := false
+	var interchanges []pendingInterchange // Checked once every network is known
+
+	// finishNetwork reports missing sections of the current network
+	finishNetwork := func() {
+		if network == "" {
+			return
+		}
+		if !hasStations {
+			l.report(header, 1, model.SeverityError, CodeMissingSection, "network '%s' does not contain a 'stations:' section", network)
+		}
+		if !hasConnections {
+			l.report(header, 1, model.SeverityError, CodeMissingSection, "network '%s' does not contain a 'connections:' section", network)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		content := strings.Split(scanner.Text(), "#")[0]
+		line := strings.TrimSpace(content)
+		if line == "" {
+			continue
+		}
+		column := strings.Index(content, line) + 1
+
+		if strings.HasPrefix(line, "---") && strings.HasSuffix(line, "---") {
+			finishNetwork()
+
+			network, header = strings.Trim(line, "- "), lineNo
+			if _, exists := l.networks[network]; !exists {
+				l.networks[network] = make(map[string]*model.Station)
+				l.stations[network] = make(map[string]position)
+				l.connections[network] = make(map[[2]string]int)
+				l.invalid[network] = make(map[string]bool)
+				l.order = append(l.order, network)
+			}
+			inStations, inConnections, inInterchanges = false, false, false
+			hasStations, hasConnections = false, false
+			reportedSection = false
+			continue
+		}
+
+		if network == "" {
+			if !reportedOutside {
+				l.report(lineNo, column, model.SeverityError, CodeSyntax, "data found outside of a network section, expected a '--- Network Name ---' header")
+				reportedOutside = true
+			}
+			continue
+		}
+
+		switch {
+		case line == "stations:":
+			inStations, hasStations, inConnections, inInterchanges = true, true, false, false
+		case line == "connections:":
+			inConnections, hasConnections, inStations, inInterchanges = true, true, false, false
+		case line == "interchanges:":
+			inInterchanges, inStations, inConnections = true, false, false
+		case inStations:
+			l.lintStation(network, lineNo, content)
+		case inConnections:
+			l.lintConnection(network, lineNo, content)
+		case inInterchanges:
+			interchanges = append(interchanges, pendingInterchange{network: network, line: lineNo, text: content})
+		case !reportedSection:
+			l.report(lineNo, column, model.SeverityError, CodeSyntax, "line outside of a 'stations:' or 'connections:' section")
+			reportedSection = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	finishNetwork()
+
+	if len(l.order) == 0 && !reportedOutside {
+		l.report(1, 1, model.SeverityError, CodeNoNetwork, "the map does not contain any networks")
+	}
+
+	l.lintInterchanges(interchanges)
+	for _, name := range l.order {
+		l.lintConnectivity(name)
+	}
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		if l.diagnostics[i].Line != l.diagnostics[j].Line {
+			return l.diagnostics[i].Line < l.diagnostics[j].Line
+		}
+		return l.diagnostics[i].Column < l.diagnostics[j].Column
+	})
+	return l.diagnostics, nil
+}
+
+// report adds a diagnostic at the given line and column
+func (l *linter) report(line, column int, severity, code, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, model.Diagnostic{
+		File:     l.file,
+		Line:     line,
+		Column:   column,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintStation checks a station line ("name,x,y[,platforms]") and adds the station if it is valid
+func (l *linter) lintStation(network string, lineNo int, content string) {
+	fields := splitFields(content, ",")
+	if len(fields) != 3 && len(fields) != 4 {
+		if len(fields) == 1 && strings.Contains(content, "-") {
+			l.report(lineNo, fields[0].column, model.SeverityError, CodeSyntax, "connection '%s' found in the 'stations:' section", fields[0].text)
+		} else {
+			l.report(lineNo, fields[0].column, model.SeverityError, CodeSyntax, "expected a station as name,x,y[,platforms], got '%s'", strings.TrimSpace(content))
+		}
+		return
+	}
+
+	name := fields[0].text
+	valid := true
+	if !stationNamePattern.MatchString(name) {
+		l.report(lineNo, fields[0].column, model.SeverityError, CodeInvalidName, "invalid station name '%s', expected lower-case letters, digits and underscores", name)
+		valid = false
+	}
+
+	coordinates := [2]int{}
+	for i, axis := range []string{"x", "y"} {
+		value, err := strconv.Atoi(fields[i+1].text)
+		if err != nil || value < 0 {
+			l.report(lineNo, fields[i+1].column, model.SeverityError, CodeInvalidCoordinate, "invalid %s coordinate '%s' for station %s, expected a non-negative integer", axis, fields[i+1].text, name)
+			valid = false
+		}
+		coordinates[i] = value
+	}
+
+	platforms := 1
+	if len(fields) == 4 {
+		value, err := strconv.Atoi(fields[3].text)
+		if err != nil || value <= 0 {
+			l.report(lineNo, fields[3].column, model.SeverityError, CodeInvalidPlatforms, "invalid number of platforms '%s' for station %s, expected a positive integer", fields[3].text, name)
+			valid = false
+		}
+		platforms = value
+	}
+
+	stations := l.networks[network]
+	if first, exists := l.stations[network][name]; exists {
+		l.report(lineNo, fields[0].column, model.SeverityError, CodeDuplicateStation, "duplicate station name '%s', first defined on line %d", name, first.line)
+		return
+	}
+	if !valid {
+		l.invalid[network][name] = true // Connections to the station are not reported again
+		return
+	}
+
+	for other, station := range stations {
+		if station.X == coordinates[0] && station.Y == coordinates[1] {
+			l.report(lineNo, fields[1].column, model.SeverityError, CodeSameCoordinates, "station %s has the same coordinates %d,%d as %s on line %d", name, coordinates[0], coordinates[1], other, l.stations[network][other].line)
+			l.invalid[network][name] = true
+			return
+		}
+	}
+
+	if len(stations) == maxStations {
+		l.report(lineNo, fields[0].column, model.SeverityError, CodeTooManyStations, "network '%s' contains more than %d stations", network, maxStations)
+	}
+	if len(stations) >= maxStations {
+		l.invalid[network][name] = true
+		return
+	}
+
+	if err := addStation(name, coordinates[0], coordinates[1], platforms, stations); err != nil {
+		l.report(lineNo, fields[0].column, model.SeverityError, CodeSyntax, "%v", err)
+		return
+	}
+	l.stations[network][name] = position{lineNo, fields[0].column}
+}
+
+// lintConnection checks a connection line ("station1-station2 [duration] [capacity=N]") and links the stations if it is valid
+func (l *linter) lintConnection(network string, lineNo int, content string) {
+	dash := strings.Index(content, "-")
+	if dash < 0 || strings.Count(content, "-") != 1 {
+		column := strings.Index(content, strings.TrimSpace(content)) + 1
+		l.report(lineNo, column, model.SeverityError, CodeSyntax, "expected a connection as station1-station2 [duration] [capacity=N], got '%s'", strings.TrimSpace(content))
+		return
+	}
+
+	left := trimField(content[:dash], 0)
+	right := splitWords(content[dash+1:], dash+1)
+	if left.text == "" || len(right) == 0 {
+		l.report(lineNo, left.column, model.SeverityError, CodeSyntax, "expected a connection as station1-station2 [duration] [capacity=N], got '%s'", strings.TrimSpace(content))
+		return
+	}
+
+	station1, station2 := left, right[0]
+	valid := true
+
+	// Check every track property on its own, so that each problem gets its column
+	var properties []string
+	for _, property := range right[1:] {
+		key, _, found := strings.Cut(property.text, "=")
+		if found && key != "duration" && key != "capacity" {
+			l.report(lineNo, property.column, model.SeverityError, CodeSyntax, "unknown track property '%s', expected a duration or capacity=N", property.text)
+			valid = false
+			continue
+		}
+		if _, err := parseTrack([]string{property.text}, station1.text, station2.text, content); err != nil {
+			if !found {
+				key = "duration"
+			}
+			l.report(lineNo, property.column, model.SeverityError, CodeInvalidTrack, "invalid %s '%s' for connection %s-%s, expected a positive integer", key, strings.TrimPrefix(property.text, key+"="), station1.text, station2.text)
+			valid = false
+			continue
+		}
+		properties = append(properties, property.text)
+	}
+
+	if station1.text == station2.text {
+		l.report(lineNo, station1.column, model.SeverityError, CodeSelfConnection, "connection from %s to itself", station1.text)
+		return
+	}
+
+	stations := l.networks[network]
+	for _, station := range []field{station1, station2} {
+		if _, exists := stations[station.text]; exists {
+			continue
+		}
+		valid = false
+		if !l.invalid[network][station.text] {
+			l.report(lineNo, station.column, model.SeverityError, CodeUnknownStation, "connection to unknown station '%s'", station.text)
+		}
+	}
+	if !valid {
+		return
+	}
+
+	key := connectionKey(station1.text, station2.text)
+	if first, exists := l.connections[network][key]; exists {
+		l.report(lineNo, station1.column, model.SeverityError, CodeDuplicateConnection, "duplicate connection between %s and %s, first defined on line %d", station1.text, station2.text, first)
+		return
+	}
+
+	track, err := parseTrack(properties, station1.text, station2.text, content)
+	if err != nil {
+		l.report(lineNo, station1.column, model.SeverityError, CodeInvalidTrack, "%v", err)
+		return
+	}
+	linkStations(stations[station1.text], stations[station2.text], track)
+	l.connections[network][key] = lineNo
+}
+
+// lintInterchanges checks the interchange lines ("network.station-network.station [duration] [capacity=N]")
+// against the networks that were read
+func (l *linter) lintInterchanges(interchanges []pendingInterchange) {
+	m := &model.Map{Networks: l.networks, Order: l.order}
+	for _, interchange := range interchanges {
+		line := strings.TrimSpace(interchange.text)
+		column := strings.Index(interchange.text, line) + 1
+
+		err := parseInterchange(line, m)
+		var value *utils.ValueError
+		var station *utils.StationError
+		var connection *utils.ConnectionError
+		switch {
+		case err == nil:
+		case errors.Is(err, utils.ErrNetworkNotExist) && errors.As(err, &value):
+			l.report(interchange.line, column, model.SeverityError, CodeUnknownStation, "interchange to unknown network '%s'", value.Value)
+		case errors.Is(err, utils.ErrStationDoesNotExistInConnections) && errors.As(err, &station):
+			l.report(interchange.line, column, model.SeverityError, CodeUnknownStation, "interchange to unknown station '%s'", station.Station)
+		case errors.Is(err, utils.ErrDuplicateConnections) && errors.As(err, &connection):
+			l.report(interchange.line, column, model.SeverityError, CodeDuplicateConnection, "duplicate interchange between %s and %s", connection.From, connection.To)
+		case errors.Is(err, utils.ErrInvalidTrack):
+			l.report(interchange.line, column, model.SeverityError, CodeInvalidTrack, "%v", err)
+		case errors.As(err, &connection):
+			l.report(interchange.line, column, model.SeverityError, CodeSyntax, "interchange %s-%s links stations of the same network, use a connection instead", connection.From, connection.To)
+		default:
+			l.report(interchange.line, column, model.SeverityError, CodeSyntax, "expected an interchange as %s, got '%s'", interchangeFormat, line)
+		}
+	}
+}
+
+// lintConnectivity warns about stations without connections and about parts of a network
+// that are not connected to its largest part
+func (l *linter) lintConnectivity(network string) {
+	stations := l.networks[network]
+	positions := l.stations[network]
+
+	// Visit stations in file order, so that warnings are stable
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return positions[names[i]].line < positions[names[j]].line })
+
+	var components [][]string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if len(stations[name].Connections) == 0 {
+			l.report(positions[name].line, positions[name].column, model.SeverityWarning, CodeIsolatedStation, "station %s has no connections", name)
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+
+		// Breadth-first search for the stations connected to this one
+		component := []string{name}
+		seen[name] = true
+		for i := 0; i < len(component); i++ {
+			for _, conn := range stations[component[i]].Connections {
+				if !seen[conn.Name] {
+					seen[conn.Name] = true
+					component = append(component, conn.Name)
+				}
+			}
+		}
+		components = append(components, component)
+	}
+
+	// The largest part, the first one on ties, is considered the network
+	largest := 0
+	for i, component := range components {
+		if len(component) > len(components[largest]) {
+			largest = i
+		}
+	}
+
+	for i, component := range components {
+		if i == largest {
+			continue
+		}
+		sort.Slice(component, func(a, b int) bool { return positions[component[a]].line < positions[component[b]].line })
+		first := positions[component[0]]
+		l.report(first.line, first.column, model.SeverityWarning, CodeUnreachable, "stations %s cannot be reached from the rest of network '%s'", summarize(component, 5), network)
+	}
+}
+
+// splitFields splits a line at the separator, trimming the fields and recording their columns
+func splitFields(content, separator string) []field {
+	var fields []field
+	offset := 0
+	for _, part := range strings.Split(content, separator) {
+		fields = append(fields, trimField(part, offset))
+		offset += len(part) + len(separator)
+	}
+	return fields
+}
+
+// trimField trims a part of a line that starts at the given offset, recording the column of its text
+func trimField(part string, offset int) field {
+	text := strings.TrimSpace(part)
+	if text == "" {
+		return field{text, offset + 1}
+	}
+	return field{text, offset + strings.Index(part, text) + 1}
+}
+
+// splitWords splits text at white space, recording the columns of the words relative to the line
+func splitWords(text string, offset int) []field {
+	var words []field
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				words = append(words, field{text[start:i], offset + start + 1})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return words
+}
+
+// connectionKey returns the same key for both directions of a connection
+func connectionKey(station1, station2 string) [2]string {
+	if station1 > station2 {
+		return [2]string{station2, station1}
+	}
+	return [2]string{station1, station2}
+}
+
+// summarize lists at most limit names, followed by how many were left out
+func summarize(names []string, limit int) string {
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:limit], ", "), len(names)-limit)
+}
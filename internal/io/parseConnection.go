@@ -4,40 +4,119 @@ import (
 	"fmt"
 	"station/internal/model"
 	"station/internal/utils"
+	"strconv"
 	"strings"
 )
 
-func parseConnection(line string, stations map[string]*model.Station, network string) error {
+// parseConnection parses a single connection line ("station1-station2 [duration] [capacity=N]") and links both stations
+func parseConnection(line string, stations map[string]*model.Station) error {
 	parts := strings.Split(line, "-")
 	if len(parts) != 2 {
-		return utils.ErrInvalidConnectionFormat(network, line)
+		return &utils.ValueError{Kind: utils.ErrInvalidConnectionFormat, Value: line, Expected: "station1-station2 [duration] [capacity=N]"}
 	}
 
+	// Everything after the second station name describes the track
+	fields := strings.Fields(parts[1])
 	station1 := strings.TrimSpace(parts[0])
-	station2 := strings.TrimSpace(parts[1])
+	station2 := ""
+	if len(fields) > 0 {
+		station2 = fields[0]
+	}
+
+	if err := checkConnection(station1, station2, stations); err != nil {
+		return err
+	}
 
+	track, err := parseTrack(fields[1:], station1, station2, line)
+	if err != nil {
+		return err
+	}
+
+	linkStations(stations[station1], stations[station2], track)
+	return nil
+}
+
+// checkConnection validates a connection between two stations before it is added.
+// The same rules apply to every map format: both stations must exist, be different,
+// and not be connected already in either direction.
+func checkConnection(station1, station2 string, stations map[string]*model.Station) error {
 	if station1 == station2 {
-		return fmt.Errorf(utils.ErrSameStartEndStation)
+		return &utils.ConnectionError{Kind: utils.ErrSameStartEndStation, From: station1, To: station2}
 	}
 
 	s1, exists1 := stations[station1]
-	s2, exists2 := stations[station2]
+	_, exists2 := stations[station2]
 
 	if !exists1 {
-		return fmt.Errorf(utils.ErrStationDoesNotExistInConnections)
+		return &utils.StationError{Kind: utils.ErrStationDoesNotExistInConnections, Station: station1, Detail: "in connection " + station1 + "-" + station2}
 	}
 	if !exists2 {
-		return fmt.Errorf(utils.ErrStationDoesNotExistInConnections)
+		return &utils.StationError{Kind: utils.ErrStationDoesNotExistInConnections, Station: station2, Detail: "in connection " + station1 + "-" + station2}
 	}
 
 	// Check for duplicate connections
 	for _, conn := range s1.Connections {
 		if conn.Name == station2 {
-			return fmt.Errorf("%s%s", utils.ErrDuplicateConnection(station1, station2), utils.Reset)
+			return &utils.ConnectionError{Kind: utils.ErrDuplicateConnections, From: station1, To: station2}
 		}
 	}
+	return nil
+}
 
+// linkStations connects two stations with a track
+func linkStations(s1, s2 *model.Station, track *model.Track) {
 	s1.Connections = append(s1.Connections, s2)
 	s2.Connections = append(s2.Connections, s1)
-	return nil
+
+	// Both stations share the same track, so its properties apply in either direction
+	if s1.Tracks == nil {
+		s1.Tracks = make(map[string]*model.Track)
+	}
+	if s2.Tracks == nil {
+		s2.Tracks = make(map[string]*model.Track)
+	}
+	s1.Tracks[s2.Name] = track
+	s2.Tracks[s1.Name] = track
+}
+
+// parseTrack parses the optional track properties that follow a connection.
+// A bare number is the travel time in turns, other properties are written as "key=value".
+func parseTrack(properties []string, station1, station2, line string) (*model.Track, error) {
+	track := model.DefaultTrack
+
+	for _, property := range properties {
+		key, value, found := strings.Cut(property, "=")
+		if !found {
+			key, value = "duration", property
+		}
+
+		switch key {
+		case "duration":
+			duration, err := strconv.Atoi(value)
+			if err != nil || duration <= 0 {
+				return nil, invalidTrack(station1, station2, "duration", value)
+			}
+			track.Duration = duration
+		case "capacity":
+			capacity, err := strconv.Atoi(value)
+			if err != nil || capacity <= 0 {
+				return nil, invalidTrack(station1, station2, "capacity", value)
+			}
+			track.Capacity = capacity
+		default:
+			return nil, &utils.ValueError{Kind: utils.ErrInvalidConnectionFormat, Value: line, Expected: "station1-station2 [duration] [capacity=N]"}
+		}
+	}
+
+	return &track, nil
+}
+
+// invalidTrack reports a track property that is not a positive integer
+func invalidTrack(station1, station2, property, value string) error {
+	return &utils.ConnectionError{
+		Kind:   utils.ErrInvalidTrack,
+		From:   station1,
+		To:     station2,
+		Detail: fmt.Sprintf("%s '%s', expected a positive integer", property, value),
+	}
 }
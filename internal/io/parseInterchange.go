@@ -0,0 +1,100 @@
+package io
+
+import (
+	"station/internal/model"
+	"station/internal/utils"
+	"strings"
+)
+
+// interchangeFormat describes an interchange line in error messages
+const interchangeFormat = "network.station-network.station [duration] [capacity=N]"
+
+// pendingInterchange is an interchange line kept until every network of the map has been read,
+// since it may refer to networks defined further down the file
+type pendingInterchange struct {
+	network string // Network whose "interchanges:" section holds the line
+	line    int    // Line number, 1-based
+	text    string // The line without comments
+}
+
+// parseInterchanges resolves the interchange lines of a map once all of its networks are known
+func parseInterchanges(pending []pendingInterchange, m *model.Map) error {
+	for _, p := range pending {
+		if err := parseInterchange(p.text, m); err != nil {
+			return &utils.LineError{Network: p.network, Line: p.line, Err: err}
+		}
+	}
+	return nil
+}
+
+// parseInterchange parses a single interchange line ("network.station-network.station [duration] [capacity=N]")
+// and adds it to the interchanges of the map. Networks are referred to by their identifier, see model.NetworkID.
+func parseInterchange(line string, m *model.Map) error {
+	parts := strings.Split(line, "-")
+	if len(parts) != 2 {
+		return &utils.ValueError{Kind: utils.ErrInvalidInterchange, Value: line, Expected: interchangeFormat}
+	}
+
+	// Everything after the second station describes the track, as for connections
+	fields := strings.Fields(parts[1])
+	if len(fields) == 0 {
+		return &utils.ValueError{Kind: utils.ErrInvalidInterchange, Value: line, Expected: interchangeFormat}
+	}
+	end1, end2 := strings.TrimSpace(parts[0]), fields[0]
+
+	track, err := parseTrack(fields[1:], end1, end2, line)
+	if err != nil {
+		return err
+	}
+	return addInterchange(end1, end2, track, m)
+}
+
+// addInterchange checks an interchange between two stations given as "network.station" and adds it to
+// the interchanges of the map. Both stations must exist in different networks, and the pair must not be
+// linked yet.
+func addInterchange(end1, end2 string, track *model.Track, m *model.Map) error {
+	network1, station1, err := resolveInterchangeEnd(end1, end1+"-"+end2, m)
+	if err != nil {
+		return err
+	}
+	network2, station2, err := resolveInterchangeEnd(end2, end1+"-"+end2, m)
+	if err != nil {
+		return err
+	}
+
+	if network1 == network2 {
+		return &utils.ConnectionError{Kind: utils.ErrInvalidInterchange, From: end1, To: end2, Detail: "both stations are in the same network, use a connection instead"}
+	}
+	for _, existing := range m.Interchanges {
+		if existing.Network1 == network1 && existing.Station1 == station1 && existing.Network2 == network2 && existing.Station2 == station2 ||
+			existing.Network1 == network2 && existing.Station1 == station2 && existing.Network2 == network1 && existing.Station2 == station1 {
+			return &utils.ConnectionError{Kind: utils.ErrDuplicateConnections, From: end1, To: end2}
+		}
+	}
+
+	m.Interchanges = append(m.Interchanges, model.Interchange{
+		Network1: network1,
+		Station1: station1,
+		Network2: network2,
+		Station2: station2,
+		Track:    track,
+	})
+	return nil
+}
+
+// resolveInterchangeEnd splits one end of an interchange ("network.station") and checks that both exist
+func resolveInterchangeEnd(end, interchange string, m *model.Map) (string, string, error) {
+	id, station, found := strings.Cut(end, ".")
+	if !found || id == "" || station == "" {
+		return "", "", &utils.ValueError{Kind: utils.ErrInvalidInterchange, Value: end, Expected: "network.station"}
+	}
+
+	network, exists := m.NetworkByID(id)
+	if !exists {
+		return "", "", &utils.ValueError{Kind: utils.ErrNetworkNotExist, Value: id}
+	}
+	if _, exists := m.Networks[network][station]; !exists {
+		return "", "", &utils.StationError{Kind: utils.ErrStationDoesNotExistInConnections, Station: station, Detail: "in interchange " + interchange}
+	}
+	return network, station, nil
+}
@@ -9,38 +9,85 @@ import (
 	"strings"
 )
 
-// parseStation parses a single station line and adds the station to the stations map
-func parseStation(line string, stations map[string]*model.Station, network string) error {
+// stationNamePattern matches the names stations may have
+var stationNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// parseStation parses a single station line ("name,x,y[,platforms]") and adds the station to the stations map
+func parseStation(line string, stations map[string]*model.Station) error {
 	parts := strings.Split(line, ",")
-	if len(parts) != 3 {
-		return utils.ErrNoConnectionsSections(network)
+	if len(parts) != 3 && len(parts) != 4 {
+		// A connection before the "connections:" header ends up here
+		return utils.ErrNoConnectionsSection
 	}
 
 	name := strings.TrimSpace(parts[0])
-	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
-		return fmt.Errorf(utils.ErrInvalidStationNames)
+	if !stationNamePattern.MatchString(name) {
+		return &utils.StationError{Kind: utils.ErrInvalidStationNames, Station: name}
 	}
 
 	x, err := strconv.Atoi(strings.TrimSpace(parts[1]))
 	if err != nil || x < 0 {
-		return fmt.Errorf("%s%s%s", utils.Red, utils.ErrInvalidCoordinate(true, x, name), utils.Reset)
+		return invalidCoordinate(name, "x", strings.TrimSpace(parts[1]))
 	}
 
 	y, err := strconv.Atoi(strings.TrimSpace(parts[2]))
 	if err != nil || y < 0 {
-		return fmt.Errorf("%s%s%s", utils.ErrInvalidCoordinate(false, y, name), utils.Reset)
+		return invalidCoordinate(name, "y", strings.TrimSpace(parts[2]))
+	}
+
+	// Stations without a platform count hold a single train
+	platforms := 1
+	if len(parts) == 4 {
+		platforms, err = strconv.Atoi(strings.TrimSpace(parts[3]))
+		if err != nil || platforms <= 0 {
+			return invalidPlatforms(name, strings.TrimSpace(parts[3]))
+		}
+	}
+
+	return addStation(name, x, y, platforms, stations)
+}
+
+// addStation validates a station and adds it to the stations map.
+// The same rules apply to every map format: names are lower-case letters, digits and underscores,
+// coordinates are non-negative, and no two stations share a name or coordinates.
+func addStation(name string, x, y, platforms int, stations map[string]*model.Station) error {
+	if !stationNamePattern.MatchString(name) {
+		return &utils.StationError{Kind: utils.ErrInvalidStationNames, Station: name}
+	}
+	if x < 0 {
+		return invalidCoordinate(name, "x", strconv.Itoa(x))
+	}
+	if y < 0 {
+		return invalidCoordinate(name, "y", strconv.Itoa(y))
+	}
+	if platforms <= 0 {
+		return invalidPlatforms(name, strconv.Itoa(platforms))
 	}
 
 	if _, exists := stations[name]; exists {
-		return fmt.Errorf(utils.ErrDuplicateStationNames)
+		return &utils.StationError{Kind: utils.ErrDuplicateStationNames, Station: name}
 	}
 
 	for _, station := range stations {
 		if station.X == x && station.Y == y {
-			return fmt.Errorf(utils.ErrSameCoordinates)
+			return &utils.StationError{Kind: utils.ErrSameCoordinates, Station: name, Detail: fmt.Sprintf("%d,%d is taken by %s", x, y, station.Name)}
 		}
 	}
 
-	stations[name] = &model.Station{Name: name, X: x, Y: y, Connections: []*model.Station{}}
+	stations[name] = &model.Station{Name: name, X: x, Y: y, Connections: []*model.Station{}, Tracks: map[string]*model.Track{}, Platforms: platforms}
 	return nil
 }
+
+// invalidCoordinate reports a coordinate that is not a non-negative integer
+func invalidCoordinate(station, axis, value string) error {
+	return &utils.StationError{
+		Kind:    utils.ErrInvalidCoordinates,
+		Station: station,
+		Detail:  fmt.Sprintf("%s coordinate '%s'", axis, value),
+	}
+}
+
+// invalidPlatforms reports a number of platforms that is not a positive integer
+func invalidPlatforms(station, value string) error {
+	return &utils.StationError{Kind: utils.ErrInvalidPlatforms, Station: station, Detail: fmt.Sprintf("'%s'", value)}
+}
@@ -0,0 +1,79 @@
+package io
+
+import (
+	"bufio"
+	"os"
+	"station/internal/model"
+	"station/internal/utils"
+	"strconv"
+	"strings"
+)
+
+// closureFormat describes a closure in error messages
+const closureFormat = "<station>:<from_turn>-<to_turn> or <station1>-<station2>:<from_turn>-<to_turn>"
+
+// ParseClosure parses a closure of the form "station:from-to" or "station1-station2:from-to".
+// A single turn may be given instead of a window, e.g. "euston:5".
+func ParseClosure(spec string) (model.Closure, error) {
+	target, window, found := strings.Cut(spec, ":")
+	target = strings.TrimSpace(target)
+	if !found || target == "" {
+		return model.Closure{}, &utils.ValueError{Kind: utils.ErrInvalidClosure, Value: spec, Expected: closureFormat}
+	}
+
+	closure := model.Closure{}
+	if from, to, isConnection := strings.Cut(target, "-"); isConnection {
+		closure.From, closure.To = strings.TrimSpace(from), strings.TrimSpace(to)
+		if closure.From == "" || closure.To == "" {
+			return model.Closure{}, &utils.ValueError{Kind: utils.ErrInvalidClosure, Value: spec, Expected: closureFormat}
+		}
+	} else {
+		closure.Station = target
+	}
+
+	first, last, isWindow := strings.Cut(window, "-")
+	if !isWindow {
+		last = first
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || start <= 0 {
+		return model.Closure{}, &utils.ValueError{Kind: utils.ErrInvalidClosure, Value: spec, Expected: "turns as positive integers"}
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(last))
+	if err != nil || end < start {
+		return model.Closure{}, &utils.ValueError{Kind: utils.ErrInvalidClosure, Value: spec, Expected: "a last turn not before the first one"}
+	}
+	closure.Start, closure.End = start, end
+
+	return closure, nil
+}
+
+// ReadClosures reads a disruptions file with one closure per line, see ParseClosure.
+// Empty lines and everything after a "#" are ignored.
+func ReadClosures(filepath string) ([]model.Closure, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var closures []model.Closure
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+
+		closure, err := ParseClosure(line)
+		if err != nil {
+			return nil, &utils.LineError{Line: lineNo, Err: err}
+		}
+		closures = append(closures, closure)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return closures, nil
+}
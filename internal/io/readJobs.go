@@ -0,0 +1,59 @@
+package io
+
+import (
+	"bufio"
+	"os"
+	"station/internal/model"
+	"station/internal/utils"
+	"strconv"
+	"strings"
+)
+
+// ParseJob parses a job description of the form "start:end:trains"
+func ParseJob(spec string) (model.Job, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return model.Job{}, &utils.ValueError{Kind: utils.ErrInvalidJob, Value: spec, Expected: "<start_station>:<end_station>:<number_of_trains>"}
+	}
+
+	trains, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+	if err != nil || trains <= 0 {
+		return model.Job{}, &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: strings.TrimSpace(parts[2])}
+	}
+
+	return model.Job{
+		Start:  strings.TrimSpace(parts[0]),
+		End:    strings.TrimSpace(parts[1]),
+		Trains: trains,
+	}, nil
+}
+
+// ReadJobs reads a jobs file with one "start:end:trains" job per line.
+// Empty lines and everything after a "#" are ignored.
+func ReadJobs(filepath string) ([]model.Job, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var jobs []model.Job
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+
+		job, err := ParseJob(line)
+		if err != nil {
+			return nil, &utils.LineError{Line: lineNo, Err: err}
+		}
+		jobs = append(jobs, job)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,63 @@
+package io
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"station/internal/model"
+	"station/internal/utils"
+	"strconv"
+	"strings"
+)
+
+// logToken matches a single train position such as "T1-victoria" or "T2-victoria(1/3)"
+var logToken = regexp.MustCompile(`^T([0-9]+)-([a-z0-9_]+)(?:\(([0-9]+)/([0-9]+)\))?$`)
+
+// ReadMovementLog reads a movement log in the format printed by the simulation.
+// Every line is one turn, starting at turn 1, and lists the positions of the trains that moved.
+// It returns the positions of all lines in order, and any error encountered.
+func ReadMovementLog(filepath string) ([]model.LogEntry, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Trailing empty lines do not count as turns
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var entries []model.LogEntry
+	for i, line := range lines {
+		for _, token := range strings.Fields(line) {
+			match := logToken.FindStringSubmatch(token)
+			if match == nil {
+				return nil, &utils.LineError{Line: i + 1, Err: &utils.ValueError{Kind: utils.ErrInvalidLogEntry, Value: token, Expected: "T<n>-<station>"}}
+			}
+
+			trainID, _ := strconv.Atoi(match[1])
+			if trainID == 0 {
+				return nil, &utils.LineError{Line: i + 1, Err: &utils.ValueError{Kind: utils.ErrInvalidLogEntry, Value: token, Expected: "T<n>-<station>"}}
+			}
+
+			entry := model.LogEntry{Turn: i + 1, TrainID: trainID - 1, Station: match[2]}
+			if match[3] != "" {
+				entry.Progress, _ = strconv.Atoi(match[3])
+				entry.Duration, _ = strconv.Atoi(match[4])
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -2,36 +2,99 @@ package io
 
 import (
 	"bufio"
-	"fmt"
+	goio "io"
 	"os"
 	"station/internal/model"
 	"station/internal/utils"
 	"strings"
+	"unicode"
 )
 
 const maxStations = 10000
 
+// StdinPath is the map path that stands for standard input
+const StdinPath = "-"
+
 // ReadMap reads and parses the network map from the specified file.
 // It returns a map of network names to maps of station names to Station structs, and any error encountered.
-func ReadMap(filepath string, startStation string, endStation string) (map[string]map[string]*model.Station, error) {
+// Use ReadMapFile when the order of the networks in the file matters.
+func ReadMap(filepath string) (map[string]map[string]*model.Station, error) {
+	m, err := ReadMapFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return m.Networks, nil
+}
+
+// ReadMapFile reads and parses all networks of the specified file, keeping them in file order.
+// Files ending in .geojson or .json are read with ReadGeoJSON, and StdinPath is read from standard input with ParseMap.
+func ReadMapFile(filepath string) (*model.Map, error) {
+	if filepath == StdinPath {
+		return ParseMap(os.Stdin)
+	}
+	if isGeoJSON(filepath) {
+		return ReadGeoJSON(filepath)
+	}
+
 	file, err := os.Open(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("%v", err)
+		return nil, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return parseMap(file)
+}
+
+// Parse reads and parses a network map from a reader, like ParseMap, returning only the networks
+func Parse(r goio.Reader) (map[string]map[string]*model.Station, error) {
+	m, err := ParseMap(r)
+	if err != nil {
+		return nil, err
+	}
+	return m.Networks, nil
+}
+
+// ParseMap reads and parses all networks of a map from a reader, keeping them in file order.
+// The map is read as a GeoJSON FeatureCollection if its first non-blank character is '{', whose features
+// without a network belong to a network named "map", and in the .map format otherwise.
+func ParseMap(r goio.Reader) (*model.Map, error) {
+	reader := bufio.NewReader(r)
+	if startsWithBrace(reader) {
+		return ParseGeoJSON(reader, "map")
+	}
+	return parseMap(reader)
+}
 
-	allNetworks := make(map[string]map[string]*model.Station)
+// startsWithBrace reports whether the first non-blank character of a reader is '{', without consuming it
+func startsWithBrace(r *bufio.Reader) bool {
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return false
+		}
+		if !unicode.IsSpace(c) {
+			r.UnreadRune()
+			return c == '{'
+		}
+	}
+}
+
+// parseMap parses a network map in the .map format
+func parseMap(r goio.Reader) (*model.Map, error) {
+	scanner := bufio.NewScanner(r)
+
+	allNetworks := model.NewMap()
 	var currentNetwork string
 	var currentStations map[string]*model.Station
+	var interchanges []pendingInterchange
 
 	inStationsSection := false
 	inConnectionsSection := false
+	inInterchangesSection := false
 	hasStationsSection := false
 	hasConnectionsSection := false
 
-	for scanner.Scan() {
+	for lineNo := 1; scanner.Scan(); lineNo++ {
 		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
 		if line == "" {
 			continue
@@ -47,12 +110,12 @@ func ReadMap(filepath string, startStation string, endStation string) (map[strin
 
 			// Start a new network
 			currentNetwork = strings.Trim(line, "- ")
-			currentStations = make(map[string]*model.Station)
-			allNetworks[currentNetwork] = currentStations
+			currentStations = allNetworks.AddNetwork(currentNetwork)
 
 			// Reset section flags
 			inStationsSection = false
 			inConnectionsSection = false
+			inInterchangesSection = false
 			hasStationsSection = false
 			hasConnectionsSection = false
 			continue
@@ -60,7 +123,7 @@ func ReadMap(filepath string, startStation string, endStation string) (map[strin
 
 		// Only process station data if we're inside a network section
 		if currentNetwork == "" {
-			return nil, fmt.Errorf("Error: Data found outside of a network section")
+			return nil, &utils.LineError{Line: lineNo, Err: utils.ErrDataOutsideNetwork}
 		}
 
 		switch line {
@@ -68,32 +131,34 @@ func ReadMap(filepath string, startStation string, endStation string) (map[strin
 			inStationsSection = true
 			hasStationsSection = true
 			inConnectionsSection = false
+			inInterchangesSection = false
 		case "connections:":
 			inConnectionsSection = true
 			hasConnectionsSection = true
 			inStationsSection = false
+			inInterchangesSection = false
+		case "interchanges:":
+			inInterchangesSection = true
+			inStationsSection = false
+			inConnectionsSection = false
 		default:
 			if inStationsSection {
 				if len(currentStations) >= maxStations {
-					return nil, fmt.Errorf("%s", utils.ErrTooManyStations)
+					return nil, &utils.LineError{Network: currentNetwork, Line: lineNo, Err: utils.ErrTooManyStations}
 				}
 
-				if err := parseStation(line, currentStations, currentNetwork); err != nil {
-					return nil, err
+				if err := parseStation(line, currentStations); err != nil {
+					return nil, &utils.LineError{Network: currentNetwork, Line: lineNo, Err: err}
 				}
 			} else if inConnectionsSection {
-
-				// err = validateStations(allNetworks, startStation, endStation)
-
-				if err := parseConnection(line, currentStations, currentNetwork); err != nil {
-					err2 := validateStations(allNetworks, startStation, endStation)
-					if err2 != nil {
-						return nil, err2
-					}
-					return nil, err
+				if err := parseConnection(line, currentStations); err != nil {
+					return nil, &utils.LineError{Network: currentNetwork, Line: lineNo, Err: err}
 				}
+			} else if inInterchangesSection {
+				// Interchanges may refer to networks further down the file, so they are resolved at the end
+				interchanges = append(interchanges, pendingInterchange{network: currentNetwork, line: lineNo, text: line})
 			} else {
-				return nil, utils.ErrNoStationsSections(currentNetwork)
+				return nil, &utils.LineError{Network: currentNetwork, Line: lineNo, Err: utils.ErrNoStationsSection}
 			}
 		}
 	}
@@ -103,8 +168,16 @@ func ReadMap(filepath string, startStation string, endStation string) (map[strin
 		return nil, err
 	}
 
-	if len(allNetworks) == 0 {
-		return nil, utils.ErrNoNetwork()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(allNetworks.Order) == 0 {
+		return nil, utils.ErrNoNetwork
+	}
+
+	if err := parseInterchanges(interchanges, allNetworks); err != nil {
+		return nil, err
 	}
 
 	return allNetworks, nil
@@ -113,47 +186,13 @@ func ReadMap(filepath string, startStation string, endStation string) (map[strin
 // validateNetwork checks that a network has both a stations section and a connections section.
 func validateNetwork(network string, hasStations, hasConnections bool) error {
 	if network == "" {
-		return utils.ErrNoNetwork()
+		return utils.ErrNoNetwork
 	}
 	if !hasStations {
-		return utils.ErrNoStationsSections(network)
+		return &utils.LineError{Network: network, Err: utils.ErrNoStationsSection}
 	}
 	if !hasConnections {
-		return utils.ErrNoConnectionsSections(network)
-	}
-	return nil
-}
-
-type errorString struct {
-	s string
-}
-
-func New(text string) error {
-	return &errorString{text}
-}
-func (e *errorString) Error() string {
-	return e.s
-}
-
-func validateStations(stations map[string]map[string]*model.Station, start string, end string) error {
-	endExist := false
-	startExist := false
-	for _, t := range stations {
-		for _, x := range t {
-			if x.Name == start {
-				startExist = true
-			}
-			if x.Name == end {
-				endExist = true
-			}
-		}
+		return &utils.LineError{Network: network, Err: utils.ErrNoConnectionsSection}
 	}
-	if !startExist {
-		return New(utils.ErrStartStationNotExist)
-	}
-	if !endExist {
-		return New(utils.ErrEndStationNotExist)
-	}
-
 	return nil
 }
@@ -1,10 +1,124 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Station represents a railway station in the network.
 type Station struct {
-	Name        string     // The unique name of the station
-	X, Y        int        // The X and Y coordinates of the station on a 2D grid
-	Connections []*Station // Slice of pointers to other Station objects that this station is directly connected to
+	Name        string            // The unique name of the station
+	X, Y        int               // The X and Y coordinates of the station on a 2D grid
+	Connections []*Station        // Slice of pointers to other Station objects that this station is directly connected to
+	Tracks      map[string]*Track // Track details keyed by the name of the connected station, shared by both ends
+	Platforms   int               // Number of platforms, 0 when not declared
+}
+
+// Capacity returns the number of trains that may stand at the station during the same turn
+func (s *Station) Capacity() int {
+	if s.Platforms > 0 {
+		return s.Platforms
+	}
+	return 1
+}
+
+// Map holds all networks of a map file
+type Map struct {
+	Networks     map[string]map[string]*Station // Stations of every network, keyed by network name and station name
+	Order        []string                       // Network names in the order they appear in the file
+	Interchanges []Interchange                  // Links between stations of different networks, in file order
+}
+
+// Interchange links a station of one network with a station of another, so that trains can change networks
+type Interchange struct {
+	Network1, Station1 string // Name of the first network and of its station
+	Network2, Station2 string // Name of the second network and of its station
+	Track              *Track // Properties of the link, shared by both directions
+}
+
+// NewMap creates an empty map
+func NewMap() *Map {
+	return &Map{Networks: make(map[string]map[string]*Station)}
+}
+
+// AddNetwork returns the stations of the named network, adding an empty network after the existing ones if needed
+func (m *Map) AddNetwork(name string) map[string]*Station {
+	if stations, exists := m.Networks[name]; exists {
+		return stations
+	}
+	stations := make(map[string]*Station)
+	m.Networks[name] = stations
+	m.Order = append(m.Order, name)
+	return stations
+}
+
+// NetworkByID returns the name of the network with the given identifier, see NetworkID
+func (m *Map) NetworkByID(id string) (string, bool) {
+	for _, name := range m.Order {
+		if NetworkID(name) == id {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NetworkID returns the identifier used to refer to a network in interchanges and qualified station names:
+// the network name in lower case, with every run of characters other than a-z, 0-9 and _ replaced by "_",
+// e.g. "london" for "London" and "paris_metro" for "Paris Metro"
+func NetworkID(name string) string {
+	var id strings.Builder
+	separated := false
+	for _, c := range strings.ToLower(strings.TrimSpace(name)) {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' {
+			id.WriteRune(c)
+			separated = false
+		} else if !separated {
+			id.WriteByte('_')
+			separated = true
+		}
+	}
+	return id.String()
+}
+
+// QualifiedName returns the name of a station prefixed with the identifier of its network, e.g. "london.st_pancras"
+func QualifiedName(network, station string) string {
+	return NetworkID(network) + "." + station
+}
+
+// Track holds the properties of the connection between two stations
+type Track struct {
+	Capacity int // Number of trains that may enter the track during the same turn, 1 for single track
+	Duration int // Number of turns a train needs to travel along the track
+}
+
+// DefaultTrack is used for connections declared without any properties
+var DefaultTrack = Track{Capacity: 1, Duration: 1}
+
+// TrackTo returns the track leading to the connected station with the given name,
+// falling back to DefaultTrack when the connection declared no properties
+func (s *Station) TrackTo(name string) Track {
+	if track, ok := s.Tracks[name]; ok && track != nil {
+		return *track
+	}
+	return DefaultTrack
+}
+
+// Movement describes a train travelling from one station to the next, or waiting for a turn when From equals To
+type Movement struct {
+	TrainID int    // The unique identifier of the moving train
+	From    string // Name of the station the train leaves
+	To      string // Name of the station the train reaches
+	Depart  int    // The first turn of the movement
+	Arrive  int    // The turn at the end of which the train stands at To
+}
+
+// LogEntry is a single train position read from a movement log, e.g. "T2-victoria(1/3)"
+type LogEntry struct {
+	Turn     int    // The turn (line of the log) the position belongs to
+	TrainID  int    // The identifier of the train, 0-based
+	Station  string // The station reached, or travelled to while in transit
+	Progress int    // Turns travelled on the current track, 0 when the train reached Station
+	Duration int    // Turns needed for the current track, 0 when the train reached Station
 }
 
 // OccupationInfo keeps track of which train occupies a station at each time step
@@ -19,3 +133,100 @@ type PathWithOccupation struct {
 	Path        []string         // Ordered slice of station names representing the train's route
 	Occupations []OccupationInfo // Slice of OccupationInfo structs, providing detailed occupation data for each step in the path
 }
+
+// Job is a group of trains travelling between the same start and end stations
+type Job struct {
+	Start  string // Name of the start station
+	End    string // Name of the end station
+	Trains int    // Number of trains to route
+}
+
+// Closure takes a station, or the connection between two stations, out of service for a window of turns.
+// No train may travel along a closed connection, or along any connection of a closed station, during
+// the window; trains already standing at a closed station wait there until it reopens.
+type Closure struct {
+	Station  string // Name of the closed station, empty when a connection is closed
+	From, To string // Names of the stations of the closed connection, empty when a station is closed
+	Start    int    // First turn of the closure
+	End      int    // Last turn of the closure
+}
+
+// Closes reports whether the closure blocks the track between two stations during the given turn
+func (c Closure) Closes(from, to string, turn int) bool {
+	if turn < c.Start || turn > c.End {
+		return false
+	}
+	if c.Station != "" {
+		return c.Station == from || c.Station == to
+	}
+	return c.From == from && c.To == to || c.From == to && c.To == from
+}
+
+// String formats the closure as in a disruptions file, e.g. "euston:3-7" or "euston-st_pancras:3-7"
+func (c Closure) String() string {
+	target := c.Station
+	if target == "" {
+		target = c.From + "-" + c.To
+	}
+	return fmt.Sprintf("%s:%d-%d", target, c.Start, c.End)
+}
+
+// Schedule describes how trains are distributed over a set of vertex-disjoint paths
+type Schedule struct {
+	Paths      [][]string // The disjoint paths in use, sorted by length
+	Lengths    []int      // Travel time of each path in turns, indexed like Paths
+	Trains     []int      // Number of trains sent along each path, indexed like Paths
+	Turns      int        // Turn in which the last train arrives, max(Li + ni - 1) over the used paths
+	LowerBound int        // Fewest turns any distribution of the trains can achieve
+}
+
+// Analysis explains what limits the number of trains that can travel between two stations
+type Analysis struct {
+	Routes      [][]string  // A largest set of routes trains can follow side by side, sorted by travel time
+	Lengths     []int       // Travel time of each route in turns, indexed like Routes
+	CutStations []string    // Stations of a minimum cut, whose platforms limit the number of routes
+	CutTracks   [][2]string // Tracks of a minimum cut, needed where no station can be cut instead
+	MinTurns    int         // Fewest turns in which the trains of the analysis can reach the end station
+}
+
+// Suggestion is a candidate new connection together with its effect on a run of trains
+type Suggestion struct {
+	From, To  string  // Names of the stations the connection would join
+	Distance  float64 // Distance between the stations on the grid
+	Turns     int     // Fewest turns the trains need with the connection
+	Reduction int     // Turns saved compared with the network as it is
+}
+
+// Resilience lists the single points of failure of a network, or of the trips between two of its stations
+type Resilience struct {
+	ArticulationPoints []string    // Stations whose loss disconnects the network, in alphabetical order
+	Bridges            [][2]string // Connections whose loss disconnects the network, in alphabetical order
+}
+
+// Conflict describes a rule of the network broken by a train during a turn
+type Conflict struct {
+	Turn    int    // The turn in which the rule is broken
+	TrainID int    // The identifier of the offending train
+	Message string // Human readable description of the broken rule
+}
+
+// Severity levels of map diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a map file by the linter
+type Diagnostic struct {
+	File     string // Path of the map file
+	Line     int    // Line of the problem, 1-based
+	Column   int    // Column of the problem, 1-based
+	Severity string // SeverityError or SeverityWarning
+	Code     string // Stable identifier of the kind of problem, e.g. "E004"
+	Message  string // Description of the problem
+}
+
+// String formats the diagnostic as "file:line:column: severity code: message"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s %s: %s", d.File, d.Line, d.Column, d.Severity, d.Code, d.Message)
+}
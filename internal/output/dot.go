@@ -0,0 +1,101 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"station/internal/model"
+	"strings"
+)
+
+// dotColors are the Graphviz colors used for the paths of consecutive trains
+var dotColors = []string{"red", "blue", "green3", "orange", "magenta", "cyan3", "brown", "purple"}
+
+// WriteDOT writes a network as an undirected Graphviz DOT graph
+// Parameters:
+//
+//	w: The writer to write the graph to
+//	network: The name of the network, used as the graph name
+//	stations: A map of all stations in the network, keyed by station name
+//	paths: Optional paths, one per train, where a repeated station means the train waits for a turn.
+//	       Every train's route is added as extra edges in its own color, labelled with the train.
+//
+// Returns:
+//
+//	Any error encountered while writing.
+//	Stations are pinned to their map coordinates with "pos" attributes, so the graph keeps its
+//	shape when rendered with neato or fdp. Tracks with a duration or capacity other than 1 are labelled.
+func WriteDOT(w io.Writer, network string, stations map[string]*model.Station, paths [][]string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "graph %s {\n", dotID(network))
+	b.WriteString("\tlayout=neato;\n")
+	b.WriteString("\tnode [shape=circle];\n")
+
+	// Write stations and tracks in a stable order
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		station := stations[name]
+		fmt.Fprintf(&b, "\t%s [pos=\"%d,%d!\"", dotID(name), station.X, station.Y)
+		if station.Platforms > 1 {
+			fmt.Fprintf(&b, ", xlabel=\"platforms=%d\"", station.Platforms)
+		}
+		b.WriteString("];\n")
+	}
+
+	for _, name := range names {
+		station := stations[name]
+		connections := make([]string, 0, len(station.Connections))
+		for _, conn := range station.Connections {
+			if conn.Name > name {
+				connections = append(connections, conn.Name) // Written once, from the smaller name
+			}
+		}
+		sort.Strings(connections)
+
+		for _, conn := range connections {
+			fmt.Fprintf(&b, "\t%s -- %s", dotID(name), dotID(conn))
+			if label := trackLabel(station.TrackTo(conn)); label != "" {
+				fmt.Fprintf(&b, " [label=%s]", dotID(label))
+			}
+			b.WriteString(";\n")
+		}
+	}
+
+	// Highlight the route of every train
+	for trainID, path := range paths {
+		color := dotColors[trainID%len(dotColors)]
+		for i := 1; i < len(path); i++ {
+			if path[i] == path[i-1] {
+				continue // Train waits at its station
+			}
+			fmt.Fprintf(&b, "\t%s -- %s [color=%s, penwidth=2, label=\"T%d\", fontcolor=%s];\n",
+				dotID(path[i-1]), dotID(path[i]), color, trainID+1, color)
+		}
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// trackLabel describes the properties of a track that differ from model.DefaultTrack
+func trackLabel(track model.Track) string {
+	var parts []string
+	if track.Duration != model.DefaultTrack.Duration {
+		parts = append(parts, fmt.Sprintf("duration=%d", track.Duration))
+	}
+	if track.Capacity != model.DefaultTrack.Capacity {
+		parts = append(parts, fmt.Sprintf("capacity=%d", track.Capacity))
+	}
+	return strings.Join(parts, " ")
+}
+
+// dotID quotes a name as a DOT identifier
+func dotID(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\\\"") + "\""
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Supported output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Supported export formats of the "export" command
+const (
+	FormatDOT     = "dot"
+	FormatGeoJSON = "geojson"
+)
+
+// WriteJSON writes the result as an indented JSON document
+func WriteJSON(w io.Writer, result Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// WriteCSV writes one row per train movement, preceded by a header row
+func WriteCSV(w io.Writer, result Result) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"network", "train", "from", "to", "depart", "arrive"}); err != nil {
+		return err
+	}
+
+	for _, train := range result.Trains {
+		for _, movement := range train.Movements {
+			record := []string{
+				result.Network,
+				train.ID,
+				movement.From,
+				movement.To,
+				strconv.Itoa(movement.Depart),
+				strconv.Itoa(movement.Arrive),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"fmt"
+	"station/internal/model"
+	"station/internal/pathfinding"
+	"strings"
+)
+
+// Result holds everything the machine-readable formats report about a simulation
+type Result struct {
+	Network string  `json:"network"`         // Name of the network selected for the run
+	Start   string  `json:"start,omitempty"` // Name of the start station, when all trains share it
+	End     string  `json:"end,omitempty"`   // Name of the end station, when all trains share it
+	Jobs    []Job   `json:"jobs"`            // Groups of trains with their start and end stations
+	Turns   int     `json:"turns"`           // Turn in which the last train arrives
+	Paths   []Path  `json:"paths"`           // Distinct routes in use
+	Trains  []Train `json:"trains"`          // Movement timeline of every train
+
+	Disruption *Disruption `json:"disruption,omitempty"` // Closures of the run and their effect, when any were given
+}
+
+// Disruption reports the closures of a run and how many turns they cost
+type Disruption struct {
+	Closures         []string `json:"closures"`         // The closures, e.g. "euston:3-7"
+	UndisruptedTurns int      `json:"undisruptedTurns"` // Turn in which the last train would arrive without the closures
+	Delta            int      `json:"delta"`            // Extra turns needed because of the closures
+}
+
+// NewDisruption reports the effect of closures on the number of turns of a run
+func NewDisruption(closures []model.Closure, undisruptedTurns, turns int) *Disruption {
+	disruption := &Disruption{Closures: []string{}, UndisruptedTurns: undisruptedTurns, Delta: turns - undisruptedTurns}
+	for _, closure := range closures {
+		disruption.Closures = append(disruption.Closures, closure.String())
+	}
+	return disruption
+}
+
+// Job is a group of trains travelling between the same start and end stations
+type Job struct {
+	Start  string   `json:"start"`  // Name of the start station
+	End    string   `json:"end"`    // Name of the end station
+	Trains []string `json:"trains"` // Identifiers of the trains of the group
+}
+
+// Path is a route used by one or more trains
+type Path struct {
+	Stations []string `json:"stations"` // Station names from start to end
+	Trains   []string `json:"trains"`   // Identifiers of the trains following the route
+}
+
+// Train is the movement timeline of a single train
+type Train struct {
+	ID        string     `json:"id"`        // Train identifier as printed by the simulation, e.g. "T1"
+	Departure int        `json:"departure"` // Turn in which the train leaves the start station
+	Arrival   int        `json:"arrival"`   // Turn in which the train reaches the end station
+	Movements []Movement `json:"movements"` // Every hop of the train, waits excluded
+}
+
+// Movement is a single hop of a train between two stations
+type Movement struct {
+	From   string `json:"from"`   // Station the train leaves
+	To     string `json:"to"`     // Station the train reaches
+	Depart int    `json:"depart"` // First turn of the hop
+	Arrive int    `json:"arrive"` // Turn at the end of which the train stands at To
+}
+
+// NewResult builds the report of a simulation from the paths returned by pathfinding.FindPaths
+// Parameters:
+//
+//	network: The name of the selected network, as returned by core.FindAppropriateMap
+//	jobs: The groups of trains, whose trains are numbered job by job
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	stations: A map of all stations in the network, keyed by station name
+//	closures: Stations and connections out of service, see pathfinding.NewSimulation
+//
+// Returns:
+//
+//	The result, or an error describing the first conflict if the trains cannot run as planned
+func NewResult(network string, jobs []model.Job, paths [][]string, stations map[string]*model.Station, closures ...model.Closure) (Result, error) {
+	sim, err := pathfinding.NewSimulation(paths, stations, closures...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Network: network, Turns: sim.Turns(), Jobs: []Job{}, Paths: []Path{}, Trains: []Train{}}
+	for trainID := range paths {
+		result.Trains = append(result.Trains, Train{ID: fmt.Sprintf("T%d", trainID+1), Movements: []Movement{}})
+	}
+
+	// Assign the trains to their jobs in order
+	trainID := 0
+	for _, job := range jobs {
+		group := Job{Start: job.Start, End: job.End, Trains: []string{}}
+		for i := 0; i < job.Trains && trainID < len(paths); i++ {
+			group.Trains = append(group.Trains, result.Trains[trainID].ID)
+			trainID++
+		}
+		result.Jobs = append(result.Jobs, group)
+	}
+	if len(jobs) == 1 {
+		result.Start, result.End = jobs[0].Start, jobs[0].End
+	}
+
+	// Record every completed hop from the event stream
+	sim.Run(func(turn int, events []pathfinding.Event) {
+		for _, event := range events {
+			if event.Kind != pathfinding.EventArrive {
+				continue
+			}
+			train := &result.Trains[event.TrainID]
+			depart := turn - event.Duration + 1
+			if len(train.Movements) == 0 {
+				train.Departure = depart
+			}
+			train.Movements = append(train.Movements, Movement{From: event.From, To: event.To, Depart: depart, Arrive: turn})
+			train.Arrival = turn
+		}
+	})
+
+	// Group trains that follow the same route
+	routes := make(map[string]int) // Index into result.Paths for each route
+	for trainID, path := range paths {
+		route := []string{path[0]}
+		for _, movement := range result.Trains[trainID].Movements {
+			route = append(route, movement.To)
+		}
+
+		key := strings.Join(route, "-")
+		if _, exists := routes[key]; !exists {
+			routes[key] = len(result.Paths)
+			result.Paths = append(result.Paths, Path{Stations: route, Trains: []string{}})
+		}
+		result.Paths[routes[key]].Trains = append(result.Paths[routes[key]].Trains, result.Trains[trainID].ID)
+	}
+
+	return result, nil
+}
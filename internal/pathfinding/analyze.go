@@ -0,0 +1,150 @@
+package pathfinding
+
+import (
+	"sort"
+	"station/internal/model"
+)
+
+// Analyze explains what limits the number of trains that can travel between two stations
+// Parameters:
+//
+//	start, end: The names of the start and end stations
+//	stations: A map of all stations in the network, keyed by station name
+//	numTrains: The number of trains the minimum number of turns is computed for
+//
+// Returns:
+//
+//	The analysis, or an error if a station does not exist, the number of trains is not positive,
+//	or no route connects the stations. The number of routes is the maximum flow of the node-split
+//	graph used by FindPaths, so stations with several platforms and tracks with a capacity above
+//	one may carry several routes. By the max-flow min-cut theorem the cut holds as many platforms
+//	and tracks as there are routes; stations are preferred over tracks when choosing the cut.
+func Analyze(start, end string, stations map[string]*model.Station, numTrains int) (model.Analysis, error) {
+	schedule, err := PlanSchedule(start, end, stations, numTrains)
+	if err != nil {
+		return model.Analysis{}, err
+	}
+	analysis := model.Analysis{MinTurns: schedule.LowerBound}
+
+	// A maximum flow of least cost gives the largest set of routes, shortest first
+	g := newFlowGraph(start, end, stations)
+	source, sink := 2*g.index[start]+1, 2*g.index[end]
+	potential := make([]int, len(g.adj))
+	for g.augment(source, sink, potential) {
+		// Augment until no residual path is left
+	}
+	routes := g.decompose(source, sink)
+	analysis.Routes, analysis.Lengths = routes.paths, routes.lengths
+
+	// Weigh tracks slightly more than stations of the same capacity, so that the minimum cut
+	// of the weighted graph is a minimum cut of the network with as few tracks as possible
+	g = newFlowGraph(start, end, stations)
+	scale := 1
+	for _, edges := range g.adj {
+		scale += len(edges)
+	}
+	for node, edges := range g.adj {
+		for i := range edges {
+			e := &g.adj[node][i]
+			if e.orig == 0 {
+				continue
+			}
+			e.orig *= scale
+			if !isStationArc(node, e.to) {
+				e.orig++
+			}
+			e.cap = e.orig
+		}
+	}
+	g.maxFlow(source, sink)
+
+	reachable := g.reachable(source)
+	for node, edges := range g.adj {
+		for _, e := range edges {
+			if e.orig == 0 || !reachable[node] || reachable[e.to] {
+				continue
+			}
+			if isStationArc(node, e.to) {
+				analysis.CutStations = append(analysis.CutStations, g.names[node/2])
+			} else {
+				analysis.CutTracks = append(analysis.CutTracks, [2]string{g.names[node/2], g.names[e.to/2]})
+			}
+		}
+	}
+	sort.Strings(analysis.CutStations)
+	sort.Slice(analysis.CutTracks, func(i, j int) bool {
+		if analysis.CutTracks[i][0] != analysis.CutTracks[j][0] {
+			return analysis.CutTracks[i][0] < analysis.CutTracks[j][0]
+		}
+		return analysis.CutTracks[i][1] < analysis.CutTracks[j][1]
+	})
+
+	return analysis, nil
+}
+
+// isStationArc reports whether an arc joins the "in" and "out" nodes of the same station
+func isStationArc(from, to int) bool {
+	return from%2 == 0 && to == from+1
+}
+
+// maxFlow pushes as much flow as possible from source to sink along shortest augmenting paths
+// (Edmonds-Karp), ignoring costs, and returns the amount of flow pushed
+func (g *flowGraph) maxFlow(source, sink int) int {
+	total := 0
+	for {
+		prevNode := make([]int, len(g.adj))
+		prevEdge := make([]int, len(g.adj))
+		for i := range prevNode {
+			prevNode[i] = -1
+		}
+		prevNode[source] = source
+
+		// Breadth-first search for the shortest residual path
+		queue := []int{source}
+		for len(queue) > 0 && prevNode[sink] == -1 {
+			node := queue[0]
+			queue = queue[1:]
+			for i, e := range g.adj[node] {
+				if e.cap > 0 && prevNode[e.to] == -1 {
+					prevNode[e.to], prevEdge[e.to] = node, i
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if prevNode[sink] == -1 {
+			return total
+		}
+
+		// Push the bottleneck capacity of the path
+		bottleneck := -1
+		for node := sink; node != source; node = prevNode[node] {
+			if c := g.adj[prevNode[node]][prevEdge[node]].cap; bottleneck == -1 || c < bottleneck {
+				bottleneck = c
+			}
+		}
+		for node := sink; node != source; node = prevNode[node] {
+			e := &g.adj[prevNode[node]][prevEdge[node]]
+			e.cap -= bottleneck
+			g.adj[node][e.rev].cap += bottleneck
+		}
+		total += bottleneck
+	}
+}
+
+// reachable marks the nodes that can be reached from source along arcs with residual capacity
+func (g *flowGraph) reachable(source int) []bool {
+	seen := make([]bool, len(g.adj))
+	seen[source] = true
+	stack := []int{source}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range g.adj[node] {
+			if e.cap > 0 && !seen[e.to] {
+				seen[e.to] = true
+				stack = append(stack, e.to)
+			}
+		}
+	}
+	return seen
+}
@@ -1,18 +1,56 @@
 package pathfinding
 
 import (
-	"fmt"
-	"sort"
 	"station/internal/core"
 	"station/internal/model"
 	"station/internal/utils"
+	"strconv"
 )
 
-// FindPaths attempts to find all possible paths and select the best ones for multiple trains
-// It returns the selected paths, their occupation information, and any error encountered
-// FindPaths attempts to find all possible paths and select the best ones for multiple trains
+// FindPaths routes multiple trains over vertex-disjoint paths computed with a max-flow
 // It returns the selected paths, their occupation information, and any error encountered
 func FindPaths(start, end string, stations map[string]*model.Station, numTrains int) ([][]string, [][]model.OccupationInfo, error) {
+	schedule, err := PlanSchedule(start, end, stations, numTrains)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Expand the schedule into one path per train, delayed trains waiting at the start station
+	paths := trainPaths(schedule, start)
+	occupations := make([][]model.OccupationInfo, len(paths))
+
+	// For each selected path, create the corresponding occupation information
+	for i, path := range paths {
+		// Create occupation information for each path, using the path index as the train ID
+		occupations[i] = core.CreateOccupations(path, i, stations)
+	}
+
+	// Return the selected paths, their occupation information, and nil error
+	return paths, occupations, nil
+}
+
+// PlanSchedule computes the schedule with the fewest turns for numTrains trains between start and end
+// It returns the schedule, including the lower bound on the number of turns, and any error encountered
+func PlanSchedule(start, end string, stations map[string]*model.Station, numTrains int) (model.Schedule, error) {
+	if err := checkSchedule(start, end, stations, numTrains); err != nil {
+		return model.Schedule{}, err
+	}
+
+	// Find the candidate sets of vertex-disjoint paths between the start and end stations
+	pathSets := findDisjointPathSets(start, end, stations, numTrains)
+
+	// If no paths are found, return an error
+	if len(pathSets) == 0 {
+		return model.Schedule{}, &utils.ConnectionError{Kind: utils.ErrNoPath, From: start, To: end}
+	}
+
+	// Select the path set that needs the fewest turns and distribute the trains over it
+	return selectOptimalPaths(pathSets, numTrains), nil
+}
+
+// checkSchedule returns the error PlanSchedule reports for stations that do not exist, the same start
+// and end station, or a number of trains that is not positive, or nil if the trains can be planned
+func checkSchedule(start, end string, stations map[string]*model.Station, numTrains int) error {
 	// Check if start and end stations exist
 	startExists := false
 	endExists := false
@@ -24,57 +62,23 @@ func FindPaths(start, end string, stations map[string]*model.Station, numTrains
 		endExists = true
 	}
 
-	if !startExists && !endExists {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrStartStationNotExist, utils.Reset)
-	}
-
 	if !startExists {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrStartStationNotExist, utils.Reset)
+		return &utils.StationError{Kind: utils.ErrStartStationNotExist, Station: start}
 	}
 
 	if !endExists {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrEndStationNotExist, utils.Reset)
+		return &utils.StationError{Kind: utils.ErrEndStationNotExist, Station: end}
 	}
 
 	// Check if start and end stations are the same
 	if start == end {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrSameStartEndStation, utils.Reset)
+		return &utils.StationError{Kind: utils.ErrSameStartEndStation, Station: start}
 	}
 
 	// Check if the number of trains is valid
 	if numTrains <= 0 {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrInvalidTrainCount, utils.Reset)
+		return &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: strconv.Itoa(numTrains)}
 	}
 
-	// Find all possible paths between the start and end stations
-	allPaths := findAllPaths(start, end, stations)
-
-	// If no paths are found, return an error
-	if len(allPaths) == 0 {
-		return nil, nil, fmt.Errorf("%s%s%s", utils.Red, utils.ErrNoPath, utils.Reset)
-	}
-
-	// Sort the paths by length (shortest first)
-	// This helps in selecting optimal paths, as shorter paths are generally preferred
-	sort.Slice(allPaths, func(i, j int) bool {
-		return len(allPaths[i]) < len(allPaths[j])
-	})
-
-	// Select the optimal paths based on the number of trains
-	// This function likely implements some logic to choose diverse and efficient paths
-	selectedPaths := selectOptimalPaths(allPaths, numTrains, start, end)
-
-	// Initialize slices to store the final paths and their occupation information
-	paths := make([][]string, len(selectedPaths))
-	occupations := make([][]model.OccupationInfo, len(selectedPaths))
-
-	// For each selected path, create the corresponding occupation information
-	for i, path := range selectedPaths {
-		paths[i] = path
-		// Create occupation information for each path, using the path index as the train ID
-		occupations[i] = core.CreateOccupations(path, i)
-	}
-
-	// Return the selected paths, their occupation information, and nil error
-	return paths, occupations, nil
+	return nil
 }
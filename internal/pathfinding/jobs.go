@@ -0,0 +1,215 @@
+package pathfinding
+
+import (
+	"station/internal/core"
+	"station/internal/model"
+)
+
+// FindJobPaths routes several groups of trains, each with its own start and end station, over one network
+// Parameters:
+//
+//	jobs: The groups of trains to route, in the order their trains are numbered
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	A slice of paths, one per train, numbered job by job, and any error encountered.
+//	Trains are planned one at a time, taking turns between the jobs, around the stations and
+//	tracks already reserved by earlier trains. A train waiting at its own start station before
+//	departure, or having reached its end station, does not take up a platform.
+func FindJobPaths(jobs []model.Job, stations map[string]*model.Station) ([][]string, error) {
+	return planJobs(jobs, stations, core.NewReservations(stations))
+}
+
+// FindDisruptedPaths routes groups of trains like FindJobPaths, around stations and connections
+// that are closed for some turns. Trains wait for a closed track to reopen, or take another route
+// when that arrives earlier.
+func FindDisruptedPaths(jobs []model.Job, stations map[string]*model.Station, closures []model.Closure) ([][]string, error) {
+	reservations := core.NewReservations(stations)
+	reservations.Close(closures)
+	return planJobs(jobs, stations, reservations)
+}
+
+// planJobs plans the trains of every job, taking turns between the jobs, around the given reservations
+func planJobs(jobs []model.Job, stations map[string]*model.Station, reservations *core.Reservations) ([][]string, error) {
+	// The optimal routes of every job on an empty network are the preferred candidates
+	routes := make([]model.Schedule, len(jobs))
+	firstTrain := make([]int, len(jobs)) // Index of the first train of each job
+	numTrains := 0
+	for i, job := range jobs {
+		schedule, err := PlanSchedule(job.Start, job.End, stations, job.Trains)
+		if err != nil {
+			return nil, err
+		}
+		routes[i] = schedule
+		firstTrain[i] = numTrains
+		numTrains += job.Trains
+	}
+
+	paths := make([][]string, numTrains)
+	planned := make([]int, len(jobs)) // Number of trains of each job planned so far
+
+	for remaining := numTrains; remaining > 0; {
+		for i, job := range jobs {
+			if planned[i] == job.Trains {
+				continue
+			}
+
+			path := planTrain(job.Start, job.End, routes[i], stations, reservations)
+			reservations.Reserve(path)
+			paths[firstTrain[i]+planned[i]] = path
+			planned[i]++
+			remaining--
+		}
+	}
+
+	return paths, nil
+}
+
+// planTrain finds the path on which a single train arrives first, given the existing reservations.
+// The preferred routes are tried with the shortest possible wait at the start station, and a
+// search through time that may also wait at intermediate stations is used when it is faster.
+func planTrain(start, end string, routes model.Schedule, stations map[string]*model.Station, reservations *core.Reservations) []string {
+	var best []string
+	bestArrival := -1
+
+	for i, route := range routes.Paths {
+		movements := core.CreateMovements(route, 0, stations)
+
+		// Past the last reserved turn the network is free, so a delay is always found
+		for delay := 0; delay <= reservations.LastTurn(); {
+			if bestArrival != -1 && delay+routes.Lengths[i] >= bestArrival {
+				break
+			}
+			next := fitsRoute(movements, delay, end, reservations)
+			if next == delay {
+				best = delayPath(route, delay)
+				bestArrival = delay + routes.Lengths[i]
+				break
+			}
+			delay = next
+		}
+	}
+
+	// Leaving the preferred routes is only worth it for a strictly earlier arrival
+	if path := findTimedPath(start, end, bestArrival-1, stations, reservations); path != nil {
+		return path
+	}
+	return best
+}
+
+// fitsRoute checks whether a train can follow a route after waiting for delay turns at its start
+// Parameters:
+//
+//	movements: The movements of the route when the train departs without waiting
+//	delay: The number of turns the train waits at its start station
+//	end: The name of the end station, which does not take up a platform
+//	reservations: The stations and tracks already used by other trains
+//
+// Returns:
+//
+//	delay if the route fits, otherwise the next delay worth trying. Closures are skipped as a whole,
+//	so that long closures do not have to be tried turn by turn.
+func fitsRoute(movements []model.Movement, delay int, end string, reservations *core.Reservations) int {
+	for _, movement := range movements {
+		depart, arrive := movement.Depart+delay, movement.Arrive+delay
+		if reopen := reservations.ClosedUntil(movement.From, movement.To, depart); reopen > 0 {
+			return max(delay+1, delay+reopen-depart+1)
+		}
+		if !reservations.CanTravel(movement.From, movement.To, depart) {
+			return delay + 1
+		}
+		if movement.To != end && !reservations.CanStand(movement.To, arrive) {
+			return delay + 1
+		}
+	}
+	return delay
+}
+
+// delayPath prepends delay turns of waiting at the start station to a route
+func delayPath(route []string, delay int) []string {
+	path := make([]string, 0, delay+len(route))
+	for i := 0; i < delay; i++ {
+		path = append(path, route[0]) // Train waits at start station
+	}
+	return append(path, route...)
+}
+
+// findTimedPath searches stations and turns together for the earliest arrival at the end station
+// Parameters:
+//
+//	start, end: The names of the start and end stations
+//	horizon: The last turn in which arriving is still of interest
+//	stations: A map of all stations in the network, keyed by station name
+//	reservations: The stations and tracks already used by other trains
+//
+// Returns:
+//
+//	The path of the train, where a repeated station means waiting for a turn, or nil if the
+//	end station cannot be reached by the horizon
+func findTimedPath(start, end string, horizon int, stations map[string]*model.Station, reservations *core.Reservations) []string {
+	if horizon < 0 {
+		return nil
+	}
+
+	// state is a station at the end of a turn
+	type state struct {
+		station string
+		turn    int
+	}
+	previous := map[state]state{{start, 0}: {}}
+	buckets := [][]string{{start}} // Stations reached at the end of each turn, grown as they are reached
+
+	for turn := 0; turn < len(buckets); turn++ {
+		for _, name := range buckets[turn] {
+			current := state{name, turn}
+
+			if name == end {
+				// Walk back through the states to recover the path
+				path := []string{}
+				for s := current; s != (state{start, 0}); s = previous[s] {
+					path = append(path, s.station)
+				}
+				path = append(path, start)
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+
+			visit := func(next state) {
+				if next.turn > horizon {
+					return
+				}
+				if _, seen := previous[next]; seen {
+					return
+				}
+				previous[next] = current
+				for len(buckets) <= next.turn {
+					buckets = append(buckets, nil)
+				}
+				buckets[next.turn] = append(buckets[next.turn], next.station)
+			}
+
+			// Wait for a turn; only the start station holds any number of waiting trains.
+			// Past the last reserved turn the network is free, so waiting no longer helps.
+			if turn < reservations.LastTurn() && (name == start || reservations.CanStand(name, turn+1)) {
+				visit(state{name, turn + 1})
+			}
+
+			// Travel to a connected station, which must have a free platform on arrival
+			for _, conn := range stations[name].Connections {
+				if conn.Name == start || !reservations.CanTravel(name, conn.Name, turn+1) {
+					continue
+				}
+				arrive := turn + stations[name].TrackTo(conn.Name).Duration
+				if conn.Name != end && !reservations.CanStand(conn.Name, arrive) {
+					continue
+				}
+				visit(state{conn.Name, arrive})
+			}
+		}
+	}
+
+	return nil
+}
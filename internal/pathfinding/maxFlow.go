@@ -0,0 +1,287 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"station/internal/model"
+)
+
+// flowEdge is a directed arc in the residual graph used by the router
+type flowEdge struct {
+	to   int // Index of the node this arc points to
+	rev  int // Index of the reverse arc in the adjacency list of "to"
+	cap  int // Remaining capacity of the arc
+	cost int // Cost (travel time in turns) of sending one train along the arc
+	orig int // Original capacity, used to tell forward arcs from residual ones
+}
+
+// pathSet is a candidate set of disjoint paths together with their travel times
+type pathSet struct {
+	paths   [][]string // Station names of each path, sorted by travel time
+	lengths []int      // Travel time of each path in turns
+}
+
+// flowGraph is a node-split residual graph of the station network.
+// Every station is represented by an "in" node (2*i) and an "out" node (2*i+1)
+// joined by an arc whose capacity is the number of trains the station can hold,
+// which turns vertex-disjointness into an ordinary max-flow problem.
+type flowGraph struct {
+	names []string       // Station names, indexed by station number
+	adj   [][]flowEdge   // Adjacency lists of residual arcs, indexed by node
+	index map[string]int // Station number for each station name
+}
+
+// newFlowGraph builds the node-split residual graph for the given network
+// Parameters:
+//
+//	start, end: The names of the start and end stations
+//	stations: A map of all stations in the network, keyed by station name
+//
+// Returns:
+//
+//	The residual graph, with the start and end stations left uncapacitated
+func newFlowGraph(start, end string, stations map[string]*model.Station) *flowGraph {
+	// Sort the station names so that the graph (and therefore the chosen paths) is deterministic
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &flowGraph{
+		names: names,
+		adj:   make([][]flowEdge, 2*len(names)),
+		index: make(map[string]int, len(names)),
+	}
+	for i, name := range names {
+		g.index[name] = i
+	}
+
+	for i, name := range names {
+		// Intermediate stations hold as many trains at a time as they have platforms
+		if name != start && name != end {
+			g.addEdge(2*i, 2*i+1, stations[name].Capacity(), 0)
+		}
+
+		for _, conn := range stations[name].Connections {
+			// Trains never need to re-enter the start or leave the end station
+			if conn.Name == start || name == end {
+				continue
+			}
+			// A track with a higher capacity lets several trains enter it in the same turn,
+			// and the cost of using it is the number of turns needed to travel along it
+			track := stations[name].TrackTo(conn.Name)
+			g.addEdge(2*i+1, 2*g.index[conn.Name], track.Capacity, track.Duration)
+		}
+	}
+
+	return g
+}
+
+// addEdge adds an arc and its zero-capacity residual twin to the graph
+func (g *flowGraph) addEdge(from, to, capacity, cost int) {
+	g.adj[from] = append(g.adj[from], flowEdge{to: to, rev: len(g.adj[to]), cap: capacity, cost: cost, orig: capacity})
+	g.adj[to] = append(g.adj[to], flowEdge{to: from, rev: len(g.adj[from]) - 1, cap: 0, cost: -cost, orig: 0})
+}
+
+// addTrack adds a track between two stations in both directions, leaving out the arcs into the start
+// station and out of the end station like newFlowGraph does
+func (g *flowGraph) addTrack(from, to, start, end string, track model.Track) {
+	for _, pair := range [][2]string{{from, to}, {to, from}} {
+		if pair[1] == start || pair[0] == end {
+			continue
+		}
+		g.addEdge(2*g.index[pair[0]]+1, 2*g.index[pair[1]], track.Capacity, track.Duration)
+	}
+}
+
+// clone copies the arcs of the graph, so that the copy can be changed and augmented without
+// touching the original. The station names and numbers are shared, as they never change.
+func (g *flowGraph) clone() *flowGraph {
+	total := 0
+	for _, edges := range g.adj {
+		total += len(edges)
+	}
+
+	// All adjacency lists share one backing array; each is capped at its own length,
+	// so that adding an arc to a copy reallocates that list instead of overwriting the next
+	flat := make([]flowEdge, 0, total)
+	adj := make([][]flowEdge, len(g.adj))
+	for node, edges := range g.adj {
+		first := len(flat)
+		flat = append(flat, edges...)
+		adj[node] = flat[first:len(flat):len(flat)]
+	}
+	return &flowGraph{names: g.names, adj: adj, index: g.index}
+}
+
+// findDisjointPathSets runs successive shortest augmenting paths (Suurballe style) between start and end.
+// After k augmentations the flow is a minimum-cost set of k vertex-disjoint paths, so every
+// candidate set the scheduler may want to use is collected along the way. Track capacities
+// are arc capacities, and a minimum-cost flow never runs a track in both directions, so the
+// chosen paths cannot meet head-on.
+// Parameters:
+//
+//	start, end: The names of the start and end stations
+//	stations: A map of all stations in the network, keyed by station name
+//	numTrains: The number of trains to schedule, which bounds the number of useful paths
+//
+// Returns:
+//
+//	A slice of path sets, where the k-th entry holds k+1 disjoint paths sorted by travel time.
+//	Paths may share stations with several platforms and tracks that take more than one train
+//	per turn, up to their capacity; a path appears several times if it can run in parallel.
+func findDisjointPathSets(start, end string, stations map[string]*model.Station, numTrains int) []pathSet {
+	return newFlowGraph(start, end, stations).disjointPathSets(start, end, numTrains)
+}
+
+// disjointPathSets runs findDisjointPathSets on a graph built by newFlowGraph, leaving the final
+// flow in its residual capacities
+func (g *flowGraph) disjointPathSets(start, end string, numTrains int) []pathSet {
+	source := 2*g.index[start] + 1
+	sink := 2 * g.index[end]
+
+	var pathSets []pathSet
+	potential := make([]int, len(g.adj))
+
+	for len(pathSets) < numTrains {
+		if !g.augment(source, sink, potential) {
+			break // The flow is maximal, no further disjoint path exists
+		}
+		pathSets = append(pathSets, g.decompose(source, sink))
+	}
+
+	return pathSets
+}
+
+// augment pushes one unit of flow along the cheapest residual path from source to sink.
+// Dijkstra is run on reduced costs so that the negative residual arcs are handled correctly.
+func (g *flowGraph) augment(source, sink int, potential []int) bool {
+	dist := make([]int, len(g.adj))
+	prevNode := make([]int, len(g.adj))
+	prevEdge := make([]int, len(g.adj))
+	for i := range dist {
+		dist[i] = math.MaxInt
+		prevNode[i] = -1
+	}
+	dist[source] = 0
+
+	pq := &nodeQueue{{node: source, dist: 0}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(nodeItem)
+		if item.dist > dist[item.node] {
+			continue // Stale queue entry
+		}
+		for i, e := range g.adj[item.node] {
+			if e.cap <= 0 {
+				continue
+			}
+			nd := item.dist + e.cost + potential[item.node] - potential[e.to]
+			if nd < dist[e.to] {
+				dist[e.to] = nd
+				prevNode[e.to] = item.node
+				prevEdge[e.to] = i
+				heap.Push(pq, nodeItem{node: e.to, dist: nd})
+			}
+		}
+	}
+
+	if dist[sink] == math.MaxInt {
+		return false
+	}
+
+	// Update the potentials so that reduced costs stay non-negative in the next round
+	for i := range potential {
+		if dist[i] != math.MaxInt {
+			potential[i] += dist[i]
+		}
+	}
+
+	// Walk back from the sink and push one unit of flow along the found path
+	for node := sink; node != source; node = prevNode[node] {
+		e := &g.adj[prevNode[node]][prevEdge[node]]
+		e.cap--
+		g.adj[node][e.rev].cap++
+	}
+
+	return true
+}
+
+// decompose splits the current flow into individual station paths from source to sink
+func (g *flowGraph) decompose(source, sink int) pathSet {
+	// Remaining flow on every forward arc, consumed as paths are extracted
+	used := make([][]int, len(g.adj))
+	for node, edges := range g.adj {
+		used[node] = make([]int, len(edges))
+		for i, e := range edges {
+			if e.orig > 0 {
+				used[node][i] = e.orig - e.cap
+			}
+		}
+	}
+
+	var set pathSet
+	for {
+		path := []string{g.names[source/2]}
+		length := 0
+		node := source
+		for node != sink {
+			next := -1
+			for i, e := range g.adj[node] {
+				if used[node][i] > 0 {
+					used[node][i]--
+					length += e.cost
+					next = e.to
+					break
+				}
+			}
+			if next == -1 {
+				break
+			}
+			// Record a station each time its "in" node is entered
+			if next%2 == 0 {
+				path = append(path, g.names[next/2])
+			}
+			node = next
+		}
+		if node != sink {
+			break // No flow left to extract
+		}
+		set.paths = append(set.paths, path)
+		set.lengths = append(set.lengths, length)
+	}
+
+	sort.Sort(byLength(set))
+	return set
+}
+
+// byLength sorts the paths of a set by travel time, keeping paths and lengths aligned
+type byLength pathSet
+
+func (s byLength) Len() int           { return len(s.paths) }
+func (s byLength) Less(i, j int) bool { return s.lengths[i] < s.lengths[j] }
+func (s byLength) Swap(i, j int) {
+	s.paths[i], s.paths[j] = s.paths[j], s.paths[i]
+	s.lengths[i], s.lengths[j] = s.lengths[j], s.lengths[i]
+}
+
+// nodeItem is an entry of the Dijkstra priority queue
+type nodeItem struct {
+	node int
+	dist int
+}
+
+// nodeQueue implements heap.Interface as a min-heap on distance
+type nodeQueue []nodeItem
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(nodeItem)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,117 @@
+package pathfinding
+
+import "station/internal/model"
+
+// ScheduleTrains distributes trains over a set of disjoint paths so that the last train arrives as early as possible
+// Parameters:
+//
+//	paths: A set of vertex-disjoint paths sorted by travel time, each path being a slice of station names
+//	lengths: The travel time of each path in turns
+//	numTrains: The number of trains to schedule
+//
+// Returns:
+//
+//	A model.Schedule holding the number of trains per path and the resulting number of turns.
+//	Its LowerBound is the minimum for this path set only; PlanSchedule widens it to the whole network.
+func ScheduleTrains(paths [][]string, lengths []int, numTrains int) model.Schedule {
+	bound := minTurns(lengths, numTrains)
+
+	// Fill every path up to the bound: the n-th train on a path of
+	// length l arrives at turn l+n-1, so the path can take bound-l+1 trains
+	trains := make([]int, len(paths))
+	total := 0
+	for i, l := range lengths {
+		if bound-l+1 > 0 {
+			trains[i] = bound - l + 1
+			total += trains[i]
+		}
+	}
+
+	// Remove the surplus from the longest paths. As bound-1 turns are not enough,
+	// the surplus is smaller than the number of used paths.
+	for i := len(paths) - 1; i >= 0 && total > numTrains; i-- {
+		if trains[i] > 0 {
+			trains[i]--
+			total--
+		}
+	}
+
+	// The arrival of the last train on each used path gives the actual turn count
+	turns := 0
+	for i, n := range trains {
+		if n > 0 && lengths[i]+n-1 > turns {
+			turns = lengths[i] + n - 1
+		}
+	}
+
+	return model.Schedule{
+		Paths:      paths,
+		Lengths:    lengths,
+		Trains:     trains,
+		Turns:      turns,
+		LowerBound: bound,
+	}
+}
+
+// minTurns returns the smallest turn count T in which numTrains trains can arrive over paths
+// of the given lengths, i.e. the smallest T with sum(max(0, T-Li+1)) >= numTrains
+func minTurns(lengths []int, numTrains int) int {
+	// arrivals counts how many trains can have arrived by the end of turn t
+	arrivals := func(t int) int {
+		count := 0
+		for _, l := range lengths {
+			if t-l+1 > 0 {
+				count += t - l + 1
+			}
+		}
+		return count
+	}
+
+	shortest := lengths[0]
+	for _, l := range lengths {
+		if l < shortest {
+			shortest = l
+		}
+	}
+
+	// Sending every train along the shortest path is always possible, which bounds the search
+	low, high := shortest, shortest+numTrains-1
+	for low < high {
+		mid := (low + high) / 2
+		if arrivals(mid) >= numTrains {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low
+}
+
+// trainPaths expands a schedule into one path per train, padding delayed trains with waits at the start station
+func trainPaths(schedule model.Schedule, start string) [][]string {
+	numTrains := 0
+	for _, n := range schedule.Trains {
+		numTrains += n
+	}
+
+	paths := make([][]string, 0, numTrains)
+
+	// Every turn each path releases its next train, so trains are numbered by departure turn
+	for delay := 0; len(paths) < numTrains; delay++ {
+		for i, path := range schedule.Paths {
+			if delay >= schedule.Trains[i] {
+				continue
+			}
+
+			// Create a new path with delay at the start if necessary
+			delayedPath := make([]string, delay+len(path))
+			for j := 0; j < delay; j++ {
+				delayedPath[j] = start // Train waits at start station
+			}
+			copy(delayedPath[delay:], path)
+			paths = append(paths, delayedPath)
+		}
+	}
+
+	return paths
+}
@@ -2,6 +2,9 @@ package pathfinding
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"station/internal/model"
 	"strings"
 )
 
@@ -9,34 +12,44 @@ import (
 // Parameters:
 //
 //	paths: A slice of paths, where each path is a slice of station names representing a train's route
-func SimTrain(paths [][]string) {
-	// Find the length of the longest path
-	maxLen := 0
-	for _, path := range paths {
-		if len(path) > maxLen {
-			maxLen = len(path) - 1 // Subtract 1 because we start from step 1, not 0
-		}
+//	stations: A map of all stations in the network, used to enforce track and station capacities
+//
+// Returns:
+//
+//	An error describing the first conflict if the trains cannot run as planned, in which case nothing is printed
+func SimTrain(paths [][]string, stations map[string]*model.Station) error {
+	sim, err := NewSimulation(paths, stations)
+	if err != nil {
+		return err
 	}
+	return PrintText(os.Stdout, sim)
+}
 
-	// Simulate each step of the train movements
-	for step := 1; step < maxLen+1; step++ {
-		changes := false        // Flag to track if any train moved in this step
-		movements := []string{} // Slice to store each train's movement
+// PrintText consumes a simulation and writes one line per turn in which a train moved.
+// Trains reaching a station are shown as "T1-victoria", trains still travelling along
+// a longer track as their destination and progress, e.g. "T2-victoria(1/3)".
+func PrintText(w io.Writer, sim *Simulation) error {
+	for sim.Next() {
+		positions := []string{} // Slice to store each moving train's position
 
-		// Check each train's position at this step
-		for trainID, path := range paths {
-			// Conditions for recording a train's position:
-			// 1. The current step is within the train's path length
-			// 2. It's either the first step (step == 0) or the train has moved to a new station
-			if step < len(path) && (step == 0 || path[step] != path[step-1]) {
-				movements = append(movements, fmt.Sprintf("T%d-%s", trainID+1, path[step]))
-				changes = true // Mark that at least one train moved
+		for _, event := range sim.Events() {
+			switch {
+			case event.Kind == EventArrive:
+				// The train has reached the next station
+				positions = append(positions, fmt.Sprintf("T%d-%s", event.TrainID+1, event.To))
+			case (event.Kind == EventDepart || event.Kind == EventTransit) && event.Progress < event.Duration:
+				// The train is still travelling, shown as its destination and progress
+				positions = append(positions, fmt.Sprintf("T%d-%s(%d/%d)", event.TrainID+1, event.To, event.Progress, event.Duration))
 			}
 		}
 
-		// Only print the line if there were any changes in this step
-		if changes {
-			fmt.Println(strings.Join(movements, " "))
+		// Only print the line if any train moved in this turn
+		if len(positions) > 0 {
+			if _, err := fmt.Fprintln(w, strings.Join(positions, " ")); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
 }
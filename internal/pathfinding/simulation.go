@@ -0,0 +1,175 @@
+package pathfinding
+
+import (
+	"station/internal/core"
+	"station/internal/model"
+	"station/internal/utils"
+)
+
+// EventKind identifies what happened to a train during a turn
+type EventKind int
+
+const (
+	EventDepart  EventKind = iota // The train leaves a station
+	EventTransit                  // The train is still travelling along a track
+	EventArrive                   // The train reaches a station
+	EventWait                     // The train stays at a station for the turn
+	EventFinish                   // The train has reached its end station
+)
+
+// String returns the lower-case name of the event kind
+func (k EventKind) String() string {
+	switch k {
+	case EventDepart:
+		return "depart"
+	case EventTransit:
+		return "transit"
+	case EventArrive:
+		return "arrive"
+	case EventWait:
+		return "wait"
+	case EventFinish:
+		return "finish"
+	}
+	return "unknown"
+}
+
+// Event is a single thing that happened to a train during a turn
+type Event struct {
+	Kind     EventKind // What happened
+	Turn     int       // The turn in which it happened
+	TrainID  int       // The identifier of the train, 0-based
+	From     string    // Station the train leaves or waits at
+	To       string    // Station the train travels to or waits at
+	Progress int       // Turns travelled on the current track, including this one
+	Duration int       // Turns needed for the current track, 1 for waits
+}
+
+// Simulation steps trains along their paths turn by turn and reports typed events
+type Simulation struct {
+	movements [][]model.Movement // Timed movements of every train
+	current   []int              // Index of the current movement of every train
+	turn      int                // The last simulated turn
+	lastTurn  int                // The turn in which the last train arrives
+	events    []Event            // Events of the last simulated turn
+}
+
+// NewSimulation prepares a simulation of trains following the given paths
+// Parameters:
+//
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	stations: A map of all stations in the network, used for travel times and to enforce capacities
+//	closures: Stations and connections out of service, which no train may travel along while closed
+//
+// Returns:
+//
+//	The simulation, or an error describing the first conflict if the trains cannot run as planned
+func NewSimulation(paths [][]string, stations map[string]*model.Station, closures ...model.Closure) (*Simulation, error) {
+	// Refuse to simulate schedules that break the rules of the network
+	conflicts := append(core.FindConflicts(paths, stations), core.FindClosureConflicts(paths, stations, closures)...)
+	if len(conflicts) > 0 {
+		first := conflicts[0]
+		for _, conflict := range conflicts[1:] {
+			if conflict.Turn < first.Turn {
+				first = conflict
+			}
+		}
+		return nil, &utils.ConflictError{Turn: first.Turn, TrainID: first.TrainID + 1, Message: first.Message}
+	}
+
+	sim := &Simulation{
+		movements: make([][]model.Movement, len(paths)),
+		current:   make([]int, len(paths)),
+	}
+
+	// Turn the paths into timed movements and find the turn in which the last train arrives
+	for trainID, path := range paths {
+		sim.movements[trainID] = core.CreateMovements(path, trainID, stations)
+		if n := len(sim.movements[trainID]); n > 0 && sim.movements[trainID][n-1].Arrive > sim.lastTurn {
+			sim.lastTurn = sim.movements[trainID][n-1].Arrive
+		}
+	}
+
+	return sim, nil
+}
+
+// Next advances the simulation by one turn and reports whether there was a turn left to simulate.
+// The events of the new turn are available through Events.
+func (s *Simulation) Next() bool {
+	if s.turn >= s.lastTurn {
+		s.events = nil
+		return false
+	}
+	s.turn++
+	s.events = s.events[:0]
+
+	// Collect the events of every train, in train order
+	for trainID, trainMovements := range s.movements {
+		for s.current[trainID] < len(trainMovements) && trainMovements[s.current[trainID]].Arrive < s.turn {
+			s.current[trainID]++
+		}
+		if s.current[trainID] == len(trainMovements) {
+			continue // The train has already reached its end station
+		}
+
+		movement := trainMovements[s.current[trainID]]
+		event := Event{
+			TrainID:  trainID,
+			Turn:     s.turn,
+			From:     movement.From,
+			To:       movement.To,
+			Progress: s.turn - movement.Depart + 1,
+			Duration: movement.Arrive - movement.Depart + 1,
+		}
+
+		if movement.From == movement.To {
+			event.Kind = EventWait
+			s.events = append(s.events, event)
+			continue
+		}
+
+		if s.turn == movement.Depart {
+			event.Kind = EventDepart
+			s.events = append(s.events, event)
+		} else if s.turn < movement.Arrive {
+			event.Kind = EventTransit
+			s.events = append(s.events, event)
+		}
+
+		if s.turn == movement.Arrive {
+			event.Kind = EventArrive
+			s.events = append(s.events, event)
+
+			// The last movement of a train ends at its end station
+			if s.current[trainID] == len(trainMovements)-1 {
+				event.Kind = EventFinish
+				s.events = append(s.events, event)
+			}
+		}
+	}
+
+	return true
+}
+
+// Turn returns the last simulated turn, 0 before the first call to Next
+func (s *Simulation) Turn() int {
+	return s.turn
+}
+
+// Turns returns the turn in which the last train arrives
+func (s *Simulation) Turns() int {
+	return s.lastTurn
+}
+
+// Events returns the events of the last simulated turn.
+// The slice is reused by the next call to Next, so consumers must copy what they keep.
+func (s *Simulation) Events() []Event {
+	return s.events
+}
+
+// Run steps through the whole simulation and hands the events of every turn to consume
+func (s *Simulation) Run(consume func(turn int, events []Event)) {
+	for s.Next() {
+		consume(s.turn, s.events)
+	}
+}
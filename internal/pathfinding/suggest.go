@@ -0,0 +1,237 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"math"
+	"runtime"
+	"sort"
+	"station/internal/core"
+	"station/internal/model"
+	"station/internal/utils"
+	"sync"
+)
+
+// CandidateConnections lists the pairs of stations that are not connected yet and lie within the
+// given distance of each other on the grid, closest pairs first
+func CandidateConnections(stations map[string]*model.Station, maxDistance float64) [][2]string {
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var candidates [][2]string
+	for i, name1 := range names {
+		for _, name2 := range names[i+1:] {
+			s1, s2 := stations[name1], stations[name2]
+			if distance(s1, s2) <= maxDistance && !core.IsConnected(s1, name2) {
+				candidates = append(candidates, [2]string{name1, name2})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return distance(stations[candidates[i][0]], stations[candidates[i][1]]) < distance(stations[candidates[j][0]], stations[candidates[j][1]])
+	})
+	return candidates
+}
+
+// SuggestConnections ranks candidate new connections by how many turns they save
+// Parameters:
+//
+//	start, end: The names of the start and end stations of the trains
+//	stations: A map of all stations in the network, keyed by station name; it is not modified
+//	numTrains: The number of trains to route
+//	candidates: The pairs of stations to try a new single track between, see CandidateConnections
+//
+// Returns:
+//
+//	The candidates that reduce the number of turns, most useful first and shorter connections first
+//	on ties, and the number of turns on the network as it is, or an error if the trains cannot be
+//	planned on the network as it is. The flow graph of the network is built once, and every
+//	candidate is evaluated with the planner behind FindPaths on a copy of its arcs with only the new
+//	track added. Candidates too far from the start and end stations to save a turn are skipped
+//	without planning, and the others are spread over all processors.
+func SuggestConnections(start, end string, stations map[string]*model.Station, numTrains int, candidates [][2]string) ([]model.Suggestion, int, error) {
+	if err := checkSchedule(start, end, stations, numTrains); err != nil {
+		return nil, 0, err
+	}
+
+	base := newFlowGraph(start, end, stations)
+	flow := base.clone()
+	pathSets := flow.disjointPathSets(start, end, numTrains)
+	if len(pathSets) == 0 {
+		return nil, 0, &utils.ConnectionError{Kind: utils.ErrNoPath, From: start, To: end}
+	}
+	baseline := selectOptimalPaths(pathSets, numTrains)
+	bound := newReductionBound(base, flow, pathSets, start, end, baseline.Turns, numTrains)
+
+	results := make([]model.Suggestion, len(candidates))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < runtime.NumCPU(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				from, to := candidates[i][0], candidates[i][1]
+				results[i] = model.Suggestion{From: from, To: to, Distance: distance(stations[from], stations[to])}
+
+				g := base.clone()
+				g.addTrack(from, to, start, end, model.DefaultTrack)
+				schedule := selectOptimalPaths(g.disjointPathSets(start, end, numTrains), numTrains)
+				results[i].Turns = schedule.Turns
+				results[i].Reduction = baseline.Turns - schedule.Turns
+			}
+		}()
+	}
+	for i, candidate := range candidates {
+		// A skipped candidate keeps the zero reduction, which leaves it out of the suggestions
+		if bound.canReduce(candidate[0], candidate[1]) || bound.canReduce(candidate[1], candidate[0]) {
+			next <- i
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	var suggestions []model.Suggestion
+	for _, suggestion := range results {
+		if suggestion.Reduction > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Reduction != suggestions[j].Reduction {
+			return suggestions[i].Reduction > suggestions[j].Reduction
+		}
+		return suggestions[i].Distance < suggestions[j].Distance
+	})
+	return suggestions, baseline.Turns, nil
+}
+
+// reductionBound tells which new tracks cannot save a turn, without planning on them.
+// By the quickest flow theorem, at most max over k of k*turns-c(k) trains arrive within turns-1
+// turns, where c(k) is the least total travel time of k disjoint paths. A flow of k paths using a
+// new track is a flow of k-1 paths of the network as it is and one path through the track, so it
+// lets no more than the k-1 paths plus turns-l trains arrive, where l is the shortest travel time
+// through the track. The track saves a turn only if that reaches the number of trains.
+type reductionBound struct {
+	g          *flowGraph
+	start, end string
+	turns      int    // Number of turns on the network as it is
+	numTrains  int    // Number of trains to route
+	arrivals   []int  // Bound on the trains arriving within turns-1 turns on k paths, indexed by k
+	fromStart  []int  // Shortest travel time from the start station to each node
+	toEnd      []int  // Shortest travel time from each node to the end station
+	maximal    bool   // Whether the largest flow found is a maximum flow
+	reachable  []bool // Nodes reached from the start station in the residual graph of that flow
+	reaching   []bool // Nodes reaching the end station in the residual graph of that flow
+}
+
+// newReductionBound prepares the bound from the graph of the network, the same graph after
+// disjointPathSets, and the path sets it returned
+func newReductionBound(base, flow *flowGraph, pathSets []pathSet, start, end string, turns, numTrains int) *reductionBound {
+	source, sink := 2*base.index[start]+1, 2*base.index[end]
+	b := &reductionBound{
+		g:         base,
+		start:     start,
+		end:       end,
+		turns:     turns,
+		numTrains: numTrains,
+		arrivals:  []int{0},
+		fromStart: base.distances(source, false),
+		toEnd:     base.distances(sink, true),
+		maximal:   len(pathSets) < numTrains,
+		reachable: flow.reachable(source),
+		reaching:  flow.reaching(sink),
+	}
+	for k, set := range pathSets {
+		cost := 0
+		for _, l := range set.lengths {
+			cost += l
+		}
+		b.arrivals = append(b.arrivals, (k+1)*turns-cost)
+	}
+	return b
+}
+
+// canReduce reports whether a new single track from one station to another might save a turn
+func (b *reductionBound) canReduce(from, to string) bool {
+	if to == b.start || from == b.end {
+		return false // newFlowGraph leaves out such arcs
+	}
+	out, in := 2*b.g.index[from]+1, 2*b.g.index[to]
+	if b.fromStart[out] == math.MaxInt || b.toEnd[in] == math.MaxInt {
+		return false
+	}
+	gain := b.turns - (b.fromStart[out] + model.DefaultTrack.Duration + b.toEnd[in])
+
+	// The other paths of a flow through the track form a smaller flow of the network as it is,
+	// which has fewer paths than the largest flow unless that is a maximum flow the track adds a path to
+	paths := len(b.arrivals) - 1
+	if b.maximal && b.reachable[out] && b.reaching[in] {
+		paths++
+	}
+	for k := 0; k < paths; k++ {
+		if b.arrivals[k]+gain >= b.numTrains {
+			return true
+		}
+	}
+	return false
+}
+
+// distances returns the shortest travel time between a node and every other node along the arcs
+// of the network, ignoring capacities; travel is towards the node when reverse is set
+func (g *flowGraph) distances(from int, reverse bool) []int {
+	dist := make([]int, len(g.adj))
+	for i := range dist {
+		dist[i] = math.MaxInt
+	}
+	dist[from] = 0
+
+	pq := &nodeQueue{{node: from, dist: 0}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(nodeItem)
+		if item.dist > dist[item.node] {
+			continue // Stale queue entry
+		}
+		for _, e := range g.adj[item.node] {
+			// Going backwards follows the residual twins of the arcs into the node
+			arc := e
+			if reverse {
+				arc = g.adj[e.to][e.rev]
+			}
+			if arc.orig <= 0 {
+				continue
+			}
+			if nd := item.dist + arc.cost; nd < dist[e.to] {
+				dist[e.to] = nd
+				heap.Push(pq, nodeItem{node: e.to, dist: nd})
+			}
+		}
+	}
+	return dist
+}
+
+// reaching marks the nodes from which sink can be reached along arcs with residual capacity
+func (g *flowGraph) reaching(sink int) []bool {
+	seen := make([]bool, len(g.adj))
+	seen[sink] = true
+	stack := []int{sink}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range g.adj[node] {
+			if g.adj[e.to][e.rev].cap > 0 && !seen[e.to] {
+				seen[e.to] = true
+				stack = append(stack, e.to)
+			}
+		}
+	}
+	return seen
+}
+
+// distance returns the straight-line distance between two stations on the grid
+func distance(s1, s2 *model.Station) float64 {
+	return math.Hypot(float64(s1.X-s2.X), float64(s1.Y-s2.Y))
+}
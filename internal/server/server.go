@@ -0,0 +1,341 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	stationio "station/internal/io"
+	"station/internal/model"
+	"station/internal/utils"
+	"station/planner"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMapSize limits the size of map documents sent to the server
+const maxMapSize = 16 << 20
+
+// maxTrains limits the number of trains of a request, all jobs and return trains included,
+// so that a single request cannot keep the planner busy indefinitely
+const maxTrains = 10000
+
+// maxTurn limits the last turn of the closures of a request, as planning and the schedule
+// grow with the length of the closures
+const maxTurn = 10000
+
+// Timeouts of the HTTP server, so that slow clients cannot hold connections open indefinitely
+const (
+	readTimeout  = 30 * time.Second
+	writeTimeout = 2 * time.Minute
+	idleTimeout  = 2 * time.Minute
+)
+
+// Server answers route planning requests over HTTP
+type Server struct {
+	maps map[string]map[string]*planner.Network // Maps loaded at startup, by map name
+}
+
+// planRequest is the body of POST /plan
+type planRequest struct {
+	Map      string       `json:"map"`      // Name of a map loaded at startup
+	MapData  string       `json:"mapData"`  // Contents of a map, used instead of a loaded map
+	Start    string       `json:"start"`    // Name of the start station
+	End      string       `json:"end"`      // Name of the end station
+	Trains   int          `json:"trains"`   // Number of trains
+	Jobs     []jobRequest `json:"jobs"`     // Further groups of trains sharing the network
+	Return   int          `json:"return"`   // Number of trains running from end back to start
+	Closures []string     `json:"closures"` // Closed stations and connections, e.g. "euston:3-7"
+}
+
+// jobRequest is a further group of trains of a plan request
+type jobRequest struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Trains int    `json:"trains"`
+}
+
+// validateResponse is the body returned by POST /validate-map
+type validateResponse struct {
+	Valid       bool                 `json:"valid"`       // Whether the map has no errors
+	Networks    []networkSummary     `json:"networks"`    // Networks of a valid map
+	Diagnostics []diagnosticResponse `json:"diagnostics"` // Errors and warnings, as reported by lint
+}
+
+// networkSummary describes a network of a validated map
+type networkSummary struct {
+	Name     string `json:"name"`
+	Stations int    `json:"stations"`
+}
+
+// diagnosticResponse is a lint diagnostic
+type diagnosticResponse struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// New creates a server for the given maps
+// Parameters:
+//
+//	maps: The maps clients may refer to, keyed by map name, each holding its networks by network name
+//
+// Returns:
+//
+//	The server, whose Handler serves the HTTP API
+func New(maps map[string]map[string]*planner.Network) *Server {
+	return &Server{maps: maps}
+}
+
+// HTTPServer returns an HTTP server listening on addr that serves the API with read, write and idle timeouts
+func (s *Server) HTTPServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+// Handler returns the HTTP handler of the API:
+//
+//	POST /plan                               Plans a schedule, returned as the JSON of -format json
+//	POST /validate-map                       Checks the map in the request body
+//	GET  /networks/{name}/visualization.png  Draws a network, with the routes of ?start=&end=&trains= if given
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /plan", s.handlePlan)
+	mux.HandleFunc("POST /validate-map", s.handleValidateMap)
+	mux.HandleFunc("GET /networks/{name}/visualization.png", s.handleVisualization)
+	return mux
+}
+
+// handlePlan plans a schedule on a loaded map or on the map sent with the request
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	var req planRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMapSize)).Decode(&req); err != nil {
+		writeError(w, statusOfBody(err), fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var networks map[string]*planner.Network
+	if req.MapData != "" {
+		var err error
+		if networks, err = planner.LoadMap(strings.NewReader(req.MapData)); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	} else if networks = s.maps[req.Map]; networks == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown map '%s'", req.Map))
+		return
+	}
+
+	jobs := []planner.Job{{Start: req.Start, End: req.End, Trains: req.Trains}}
+	for _, job := range req.Jobs {
+		jobs = append(jobs, planner.Job{Start: job.Start, End: job.End, Trains: job.Trains})
+	}
+	if err := checkTrainCount(jobs, req.Return); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var closures []planner.Closure
+	for _, spec := range req.Closures {
+		closure, err := stationio.ParseClosure(spec)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if closure.End > maxTurn {
+			writeError(w, http.StatusBadRequest, &utils.ValueError{Kind: utils.ErrInvalidClosure, Value: spec, Expected: fmt.Sprintf("a closure ending by turn %d", maxTurn)})
+			return
+		}
+		closures = append(closures, closure)
+	}
+
+	network, err := planner.SelectNetwork(networks, jobs...)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	schedule, err := planner.Plan(network, req.Start, req.End, req.Trains, planner.Options{Jobs: jobs[1:], Return: req.Return, Closures: closures})
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	logWriteError(r, schedule.WriteJSON(w))
+}
+
+// handleValidateMap checks the map in the request body and reports every problem found
+func (s *Server) handleValidateMap(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMapSize))
+	if err != nil {
+		writeError(w, statusOfBody(err), err)
+		return
+	}
+
+	response := validateResponse{Valid: true, Networks: []networkSummary{}, Diagnostics: []diagnosticResponse{}}
+	var diagnostics []model.Diagnostic
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		// GeoJSON maps are not linted, the first problem found while loading is reported
+		if _, err := planner.LoadMap(strings.NewReader(string(data))); err != nil {
+			diagnostics = append(diagnostics, model.Diagnostic{Severity: model.SeverityError, Message: err.Error()})
+		}
+	} else if diagnostics, err = stationio.LintReader(strings.NewReader(string(data)), "map"); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, d := range diagnostics {
+		response.Diagnostics = append(response.Diagnostics, diagnosticResponse{
+			Line: d.Line, Column: d.Column, Severity: d.Severity, Code: d.Code, Message: d.Message,
+		})
+		if d.Severity == model.SeverityError {
+			response.Valid = false
+		}
+	}
+
+	// Describe the networks of a valid map as the planner loads them
+	if response.Valid {
+		networks, err := planner.LoadMap(strings.NewReader(string(data)))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		for name, network := range networks {
+			response.Networks = append(response.Networks, networkSummary{Name: name, Stations: len(network.Stations())})
+		}
+		sort.Slice(response.Networks, func(i, j int) bool { return response.Networks[i].Name < response.Networks[j].Name })
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleVisualization draws a network of the loaded maps as a PNG image
+func (s *Server) handleVisualization(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	network := s.findNetwork(r.URL.Query().Get("map"), name)
+	if network == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown network '%s'", name))
+		return
+	}
+
+	// Without a job only the network is drawn
+	query := r.URL.Query()
+	if query.Get("start") == "" && query.Get("end") == "" {
+		w.Header().Set("Content-Type", "image/png")
+		logWriteError(r, network.WritePNG(w))
+		return
+	}
+
+	trains, err := strconv.Atoi(query.Get("trains"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: query.Get("trains")})
+		return
+	}
+	if err := checkTrainCount([]planner.Job{{Trains: trains}}, 0); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	schedule, err := planner.Plan(network, query.Get("start"), query.Get("end"), trains, planner.Options{})
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	logWriteError(r, schedule.WritePNG(w))
+}
+
+// findNetwork returns the network with the given name, from the named map if given,
+// otherwise from the first map containing it in alphabetical order
+func (s *Server) findNetwork(mapName, name string) *planner.Network {
+	if mapName != "" {
+		return s.maps[mapName][name]
+	}
+
+	mapNames := make([]string, 0, len(s.maps))
+	for mapName := range s.maps {
+		mapNames = append(mapNames, mapName)
+	}
+	sort.Strings(mapNames)
+	for _, mapName := range mapNames {
+		if network := s.maps[mapName][name]; network != nil {
+			return network
+		}
+	}
+	return nil
+}
+
+// checkTrainCount rejects requests for more than maxTrains trains in total.
+// Negative counts are left to the planner, which rejects them.
+func checkTrainCount(jobs []planner.Job, returnTrains int) error {
+	counts := []int{returnTrains}
+	for _, job := range jobs {
+		counts = append(counts, job.Trains)
+	}
+
+	total := 0
+	for _, count := range counts {
+		// Every count is checked before it is added, so the total cannot overflow
+		if count > maxTrains {
+			total = count
+			break
+		}
+		total += count
+	}
+	if total > maxTrains {
+		return &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: strconv.Itoa(total), Expected: fmt.Sprintf("at most %d trains per request", maxTrains)}
+	}
+	return nil
+}
+
+// statusOfBody returns the HTTP status for an error reading a request body
+func statusOfBody(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// statusOf returns the HTTP status for a planning error
+func statusOf(err error) int {
+	if errors.Is(err, utils.ErrStartStationNotExist) || errors.Is(err, utils.ErrEndStationNotExist) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, utils.ErrNoPath) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// writeError writes an error as a JSON document of the form {"error": "..."}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeJSON writes a value as a JSON document with the given status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// logWriteError logs an error writing the response to a request. The status has been sent by then,
+// so the client can no longer be told.
+func logWriteError(r *http.Request, err error) {
+	if err != nil {
+		log.Printf("Error writing response to %s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
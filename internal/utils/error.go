@@ -1,88 +1,151 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 )
 
-const (
+// Sentinel errors describe the kind of a problem and can be matched with errors.Is.
+// The structured errors below wrap them with the station, connection, value or line involved.
+// Messages carry no colour; the CLI colours them when printing.
+var (
 	// Command Line Errors
-	ErrIncorrectArgCount = "Error: Incorrect number of command line arguments"
-	ErrTooFewArgs        = "Error: Too few command line arguments"
-	ErrTooManyArgs       = "Error: Too many command line arguments"
+	ErrIncorrectArgCount   = errors.New("Error: Incorrect number of command line arguments")
+	ErrTooFewArgs          = errors.New("Error: Too few command line arguments")
+	ErrTooManyArgs         = errors.New("Error: Too many command line arguments")
+	ErrInvalidFormat       = errors.New("Error: Unknown output format")
+	ErrInvalidVizFormat    = errors.New("Error: Unknown visualization format")
+	ErrInvalidExportFormat = errors.New("Error: Unknown export format")
 
 	// Station Errors
-	ErrStartStationNotExist             = "Error: Start station does not exist"
-	ErrEndStationNotExist               = "Error: End station does not exist"
-	ErrSameStartEndStation              = "Error: Start and end station are the same"
-	ErrDuplicateStationNames            = "Error: Duplicate station names"
-	ErrInvalidStationNames              = "Error: Invalid station name in network"
-	ErrSameCoordinates                  = "Error: Two stations exist at the same coordinates"
-	ErrStationDoesNotExistInConnections = "Error: Connetion to non existing station"
+	ErrStartStationNotExist             = errors.New("Error: Start station does not exist")
+	ErrEndStationNotExist               = errors.New("Error: End station does not exist")
+	ErrSameStartEndStation              = errors.New("Error: Start and end station are the same")
+	ErrDuplicateStationNames            = errors.New("Error: Duplicate station names")
+	ErrInvalidStationNames              = errors.New("Error: Invalid station name in network")
+	ErrSameCoordinates                  = errors.New("Error: Two stations exist at the same coordinates")
+	ErrStationDoesNotExistInConnections = errors.New("Error: Connetion to non existing station")
+	ErrInvalidCoordinates               = errors.New("Error: Coordinates which are not valid positive integers")
+	ErrInvalidPlatforms                 = errors.New("Error: Invalid number of platforms")
 
 	// Connection Errors
-	ErrNoPath                = "Error: no paths found"
-	ErrDuplicateConnections  = "Error: Duplicate connections, including those which are described in reverse"
-	ErrNonexistentConnection = "Error: Connection with a station which does not exist"
+	ErrNoPath                  = errors.New("Error: no paths found")
+	ErrDuplicateConnections    = errors.New("Error: Duplicate connections, including those which are described in reverse")
+	ErrNonexistentConnection   = errors.New("Error: Connection with a station which does not exist")
+	ErrInvalidConnectionFormat = errors.New("Error: Invalid connection format")
+	ErrInvalidTrack            = errors.New("Error: Invalid track property")
+	ErrInvalidInterchange      = errors.New("Error: Invalid interchange")
 
 	// Input Validation Errors
-	ErrInvalidTrainCount  = "Error: Number of trains is not a valid positive integer"
-	ErrInvalidCoordinates = "Error: Coordinates which are not valid positive integers"
+	ErrInvalidTrainCount = errors.New("Error: Number of trains is not a valid positive integer")
+	ErrInvalidJob        = errors.New("Error: Invalid job")
+	ErrNoJobs            = errors.New("Error: The jobs file does not contain any jobs")
+	ErrInvalidLogEntry   = errors.New("Error: Invalid movement")
+	ErrInvalidClosure    = errors.New("Error: Invalid closure")
 
 	// Map Structure Errors
-	ErrNoStationsSection    = "Error: The map does not contain a \"stations:\" section"
-	ErrNoConnectionsSection = "Error: The map does not contain a \"connections:\" section"
-	ErrTooManyStations      = "Error: Map contains more than 10000 stations"
+	ErrNoStationsSection    = errors.New("Error: The map does not contain a \"stations:\" section")
+	ErrNoConnectionsSection = errors.New("Error: The map does not contain a \"connections:\" section")
+	ErrTooManyStations      = errors.New("Error: Map contains more than 10000 stations")
+	ErrNoNetwork            = errors.New("Error: The map does not contain any networks")
+	ErrNetworkNotExist      = errors.New("Error: Network does not exist")
+	ErrDataOutsideNetwork   = errors.New("Error: Data found outside of a network section")
+	ErrInvalidGeoJSON       = errors.New("Error: Invalid GeoJSON map")
+
+	// Schedule Errors
+	ErrScheduleConflict = errors.New("Error: Schedule conflict")
+	ErrValidationFailed = errors.New("Error: Schedule is invalid")
+	ErrLintFailed       = errors.New("Error: Map is invalid")
 )
 
-// Enhanced error messages
-func ErrDuplicateConnection(station1, station2 string) string {
-	return fmt.Sprintf("Error: Duplicate connection between %s and %s", station1, station2)
+// StationError is a problem with a particular station
+type StationError struct {
+	Kind    error  // Sentinel error describing the problem
+	Station string // Name of the station
+	Detail  string // Optional explanation, e.g. the offending value
 }
 
-func ErrInvalidCoordinate(isX bool, coord int, stationName string) string {
-	coordType := "x"
-	if !isX {
-		coordType = "y"
+func (e *StationError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%v: %s (%s)", e.Kind, e.Station, e.Detail)
 	}
-	return fmt.Sprintf("Error: Invalid %s coordinate for station %s", coordType, stationName)
+	return fmt.Sprintf("%v: %s", e.Kind, e.Station)
 }
 
-func ErrNoConnectionsSections(network string) error {
-	return fmt.Errorf("Error: Network '%s' does not contain a 'connections:' section", network)
+func (e *StationError) Unwrap() error {
+	return e.Kind
 }
 
-// func ErrInvalidStationFormat(network, line string) error {
-// 	return fmt.Errorf("Error: Invalid station format in network %s: %s", network, line)
-// }
+// ConnectionError is a problem with a connection between two stations
+type ConnectionError struct {
+	Kind     error  // Sentinel error describing the problem
+	From, To string // Names of the connected stations
+	Detail   string // Optional explanation, e.g. the offending value
+}
+
+func (e *ConnectionError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%v: %s-%s (%s)", e.Kind, e.From, e.To, e.Detail)
+	}
+	return fmt.Sprintf("%v: %s-%s", e.Kind, e.From, e.To)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Kind
+}
 
-func ErrInvalidCoordinat(isX bool, coord int, stationName string) error {
-	coordType := "x"
-	if !isX {
-		coordType = "y"
+// ValueError is an argument or field whose value is not accepted
+type ValueError struct {
+	Kind     error  // Sentinel error describing the problem
+	Value    string // The rejected value
+	Expected string // Optional description of the accepted values
+}
+
+func (e *ValueError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("%v '%s', expected %s", e.Kind, e.Value, e.Expected)
 	}
-	return fmt.Errorf("Error: Invalid %s coordinate for station %s", coordType, stationName)
+	return fmt.Sprintf("%v '%s'", e.Kind, e.Value)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Kind
 }
 
-func ErrInvalidConnectionFormat(network, line string) error {
-	return fmt.Errorf("Error: Invalid connection format in network %s: %s", network, line)
+// LineError locates a problem in an input file
+type LineError struct {
+	Network string // Network the problem was found in, empty outside of map files
+	Line    int    // Line number, 1-based, 0 if unknown
+	Err     error  // The problem
 }
 
-func ErrSameStationConnection(station, network string) error {
-	return fmt.Errorf("Error: Start and end station '%s' are the same in network '%s'", station, network)
+func (e *LineError) Error() string {
+	switch {
+	case e.Network != "" && e.Line > 0:
+		return fmt.Sprintf("%v (network '%s', line %d)", e.Err, e.Network, e.Line)
+	case e.Network != "":
+		return fmt.Sprintf("%v (network '%s')", e.Err, e.Network)
+	case e.Line > 0:
+		return fmt.Sprintf("%v (line %d)", e.Err, e.Line)
+	}
+	return e.Err.Error()
 }
 
-func ErrStationNotExist(station, network string) error {
-	return fmt.Errorf("Error: Station '%s' does not exist in network '%s'", station, network)
+func (e *LineError) Unwrap() error {
+	return e.Err
 }
 
-func ErrNoStationsSections(network string) error {
-	return fmt.Errorf("Error: Network '%s' does not contain a 'stations:' section", network)
+// ConflictError is a train movement that breaks the rules of the network
+type ConflictError struct {
+	Turn    int    // The turn of the conflict
+	TrainID int    // The train involved, 1-based as in the output
+	Message string // Description of the conflict
 }
 
-func ErrNoNetwork() error {
-	return fmt.Errorf("Error: The map does not contain any networks")
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("Error: Turn %d, T%d: %s", e.Turn, e.TrainID, e.Message)
 }
 
-// func ErrDataOutsideSection(network string) error {
-// 	return fmt.Errorf("Error: Found data outside of stations or connections section in network '%s'", network)
-// }
+func (e *ConflictError) Unwrap() error {
+	return ErrScheduleConflict
+}
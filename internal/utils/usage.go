@@ -16,9 +16,19 @@ func PrintUsage() {
 	fmt.Println(string(Green) + "Usage:" + string(Reset))
 	fmt.Println(string(Cyan) + "  1. From the root folder:" + string(Reset))
 	fmt.Println(string(Yellow) + "  go run . <network_map> <start_station> <end_station> <number_of_trains>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . [-job <start:end:trains>]... [-jobs <jobs_file>] <network_map>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . validate [-network <name>] <network_map> <start_station> <end_station> <movement_log>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . lint <network_map>..." + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . export [-o <file>] [-network <name>] dot <network_map> [<start_station> <end_station> <number_of_trains>]" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . export [-o <file>] [-network <name>] geojson <network_map>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . analyze [-network <name>] <network_map> <start_station> <end_station> <number_of_trains>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . suggest [-distance <max>] [-top <n>] [-network <name>] <network_map> <start_station> <end_station> <number_of_trains>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . resilience [-v] [-network <name>] <network_map> [<start_station> <end_station>]" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . list-networks <network_map>" + string(Reset))
+	fmt.Println(string(Yellow) + "  go run . serve [-addr <host:port>] [<name>=]<network_map>..." + string(Reset))
 	fmt.Println()
 	fmt.Println(string(Green) + "Arguments:" + string(Reset))
-	fmt.Println(string(Cyan) + "  <network_map>      " + string(Reset) + "Path to the network map file")
+	fmt.Println(string(Cyan) + "  <network_map>      " + string(Reset) + "Path to the network map file (.map, or .geojson for GeoJSON), or - for stdin")
 	fmt.Println(string(Cyan) + "  <start_station>    " + string(Reset) + "Name of the start station")
 	fmt.Println(string(Cyan) + "  <end_station>      " + string(Reset) + "Name of the end station")
 	fmt.Println(string(Cyan) + "  <number_of_trains> " + string(Reset) + "Number of trains (positive integer)")
@@ -26,6 +36,15 @@ func PrintUsage() {
 	fmt.Println(string(Green) + "Flags:" + string(Reset))
 	fmt.Println(string(Cyan) + "  -h, --help         " + string(Reset) + "Show this help message")
 	fmt.Println(string(Cyan) + "  -v                 " + string(Reset) + "Enable visualization (creates a PNG image of the network and paths)")
+	fmt.Println(string(Cyan) + "  -viz-format <fmt>  " + string(Reset) + "Visualization format: png (default) or svg")
+	fmt.Println(string(Cyan) + "  -animate <file>    " + string(Reset) + "Write an animated GIF of the simulation, one frame per turn")
+	fmt.Println(string(Cyan) + "  -format <format>   " + string(Reset) + "Output format: text (default), json or csv")
+	fmt.Println(string(Cyan) + "  -job <s:e:n>       " + string(Reset) + "Route n trains from station s to station e (repeatable)")
+	fmt.Println(string(Cyan) + "  -jobs <file>       " + string(Reset) + "Read start:end:trains jobs from a file, one per line")
+	fmt.Println(string(Cyan) + "  -network <name>    " + string(Reset) + "Use the named network when several contain the stations")
+	fmt.Println(string(Cyan) + "  -return <n>        " + string(Reset) + "Also run n trains from the end station back to the start station")
+	fmt.Println(string(Cyan) + "  -close <spec>      " + string(Reset) + "Close a station (s:from-to) or connection (a-b:from-to) for some turns (repeatable)")
+	fmt.Println(string(Cyan) + "  -closures <file>   " + string(Reset) + "Read closures from a file, one per line")
 	fmt.Println()
 	fmt.Println(string(Green) + "Running the Program:" + string(Reset))
 	fmt.Println(string(Cyan) + "  1. Navigate to the project root directory" + string(Reset))
@@ -0,0 +1,115 @@
+package visualization
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"station/internal/model"
+	"station/internal/pathfinding"
+)
+
+// frameDelay is the time every turn is shown for, in hundredths of a second
+const frameDelay = 80
+
+// framePalette holds every color drawn by drawNetwork and drawTrains, so frames convert without loss
+var framePalette = append(color.Palette{
+	color.White,
+	color.Black,
+	color.RGBA{200, 200, 200, 255}, // Grid
+	color.RGBA{100, 100, 100, 255}, // Connections
+	stationColor,                   // Stations and label backgrounds
+}, pathPalette()...)
+
+// pathPalette returns the path colors as palette entries
+func pathPalette() color.Palette {
+	colors := make(color.Palette, len(pathColors))
+	for i, c := range pathColors {
+		colors[i] = c
+	}
+	return colors
+}
+
+// trainPosition is where a train is at the end of a turn, travelling from one station to another
+type trainPosition struct {
+	from, to string
+	progress float64 // Fraction of the track covered, 1 once the train stands at the station
+}
+
+// CreateAnimation generates an animated GIF with one frame per simulation turn,
+// showing every train as a labelled marker moving along its path
+// Parameters:
+//
+//	stations: A map of all stations in the network, keyed by station name
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//	filename: The name of the GIF file to write
+//
+// Returns:
+//
+//	An error if the trains cannot run as planned or the file cannot be written
+func CreateAnimation(stations map[string]*model.Station, paths [][]string, filename string) error {
+	sim, err := pathfinding.NewSimulation(paths, stations)
+	if err != nil {
+		return err
+	}
+
+	// The network and paths are the same in every frame
+	background, l := drawNetwork(stations, paths)
+
+	// All trains stand at their start stations before the first turn
+	positions := make([]trainPosition, len(paths))
+	for trainID, path := range paths {
+		positions[trainID] = trainPosition{from: path[0], to: path[0], progress: 1}
+	}
+
+	anim := &gif.GIF{}
+	addFrame := func(turn int) {
+		frame := image.NewRGBA(background.Bounds())
+		draw.Draw(frame, frame.Bounds(), background, image.Point{}, draw.Src)
+		drawTrains(frame, stations, positions, l)
+		drawLargeText(frame, fmt.Sprintf("TURN %d", turn), 10, 10, color.Black, 3)
+
+		paletted := image.NewPaletted(frame.Bounds(), framePalette)
+		draw.Draw(paletted, paletted.Bounds(), frame, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, frameDelay)
+	}
+
+	addFrame(0)
+	sim.Run(func(turn int, events []pathfinding.Event) {
+		for _, event := range events {
+			positions[event.TrainID] = trainPosition{
+				from:     event.From,
+				to:       event.To,
+				progress: float64(event.Progress) / float64(event.Duration),
+			}
+		}
+		addFrame(turn)
+	})
+
+	return saveFile(filename, "Animation", func(w io.Writer) error {
+		return gif.EncodeAll(w, anim)
+	})
+}
+
+// drawTrains draws a marker labelled T1..Tn for every train at its position.
+// Labels of trains at the same spot are stacked so that all of them stay readable.
+func drawTrains(img *image.RGBA, stations map[string]*model.Station, positions []trainPosition, l layout) {
+	stacked := make(map[image.Point]int) // Labels already drawn at each spot
+
+	for trainID, position := range positions {
+		from, to := stations[position.from], stations[position.to]
+		x, y := l.point(
+			float64(from.X)+float64(to.X-from.X)*position.progress,
+			float64(from.Y)+float64(to.Y-from.Y)*position.progress,
+		)
+
+		spot := image.Point{X: x, Y: y}
+		trainColor := pathColors[trainID%len(pathColors)]
+		drawCircle(img, x, y, 8, trainColor)
+		drawLargeText(img, fmt.Sprintf("T%d", trainID+1), x-20, y+12+stacked[spot]*16, trainColor, 2)
+		stacked[spot]++
+	}
+}
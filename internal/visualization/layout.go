@@ -0,0 +1,86 @@
+package visualization
+
+import (
+	"math"
+	"station/internal/model"
+)
+
+// Visualization formats selectable with -viz-format
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// layout places network coordinates on a canvas, shared by the PNG, GIF and SVG backends
+type layout struct {
+	width, height int // Size of the canvas in pixels
+	margin        int // Space between the canvas border and the plotted area
+	scale         int // Pixels per network coordinate unit
+}
+
+// newLayout fits the bounding box of the network into a canvas of the given size
+// Parameters:
+//
+//	stations: A map of all stations in the network, keyed by station name
+//	width, height: The size of the canvas in pixels
+//	margin: The space to keep free around the plotted area
+//
+// Returns:
+//
+//	The layout, with the origin of the network in the bottom left corner of the plotted area
+func newLayout(stations map[string]*model.Station, width, height, margin int) layout {
+	maxX, maxY := bounds(stations)
+
+	// Calculate scaling factor based on the bounding box
+	scale := int(math.Min(float64(width-margin*2)/float64(maxX), float64(height-margin*2)/float64(maxY))) - 1
+	if scale < 1 {
+		scale = 1
+	}
+
+	return layout{width: width, height: height, margin: margin, scale: scale}
+}
+
+// canvasSize grows a canvas of the given size so that neighbouring grid points of the network lie at
+// least pixelsPerUnit pixels apart, instead of squeezing large maps
+func canvasSize(stations map[string]*model.Station, width, height, margin, pixelsPerUnit int) (int, int) {
+	maxX, maxY := bounds(stations)
+	if size := maxX*pixelsPerUnit + 2*margin; size > width {
+		width = size
+	}
+	if size := maxY*pixelsPerUnit + 2*margin; size > height {
+		height = size
+	}
+	return width, height
+}
+
+// bounds returns the largest coordinates of the network, at least 1 so that they can be divided by
+func bounds(stations map[string]*model.Station) (int, int) {
+	maxX, maxY := 1, 1
+	for _, station := range stations {
+		if station.X > maxX {
+			maxX = station.X
+		}
+		if station.Y > maxY {
+			maxY = station.Y
+		}
+	}
+	return maxX, maxY
+}
+
+// point converts network coordinates into pixel coordinates, with the y axis pointing up
+func (l layout) point(x, y float64) (int, int) {
+	return l.margin + int(math.Round(x*float64(l.scale))), l.height - l.margin - int(math.Round(y*float64(l.scale)))
+}
+
+// station returns the pixel coordinates of a station
+func (l layout) station(s *model.Station) (int, int) {
+	return l.point(float64(s.X), float64(s.Y))
+}
+
+// gridStep returns the spacing of the background grid in pixels
+func (l layout) gridStep() int {
+	if l.scale < 2 {
+		return 1
+	}
+	return l.scale / 2
+}
@@ -0,0 +1,152 @@
+package visualization
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"station/internal/core"
+	"station/internal/model"
+	"strings"
+)
+
+// svgPixelsPerUnit is the smallest distance between neighbouring grid points in the SVG,
+// so that the canvas grows with large maps instead of squeezing them
+const svgPixelsPerUnit = 40
+
+// svgColors are the colors used for the distinct routes in the SVG
+var svgColors = []string{"#e6194b", "#3cb44b", "#ffa500", "#ff00ff", "#4363d8", "#42d4f4", "#9a6324", "#911eb4"}
+
+// route is a distinct sequence of stations used by one or more trains
+type route struct {
+	stations []string // Stations of the route, without waits
+	trains   []int    // Identifiers of the trains using the route, 0-based
+}
+
+// CreateSVGVisualization generates an SVG image of the network and train paths
+func CreateSVGVisualization(stations map[string]*model.Station, paths [][]string) error {
+	return saveFile("network_visualization.svg", "Visualization", func(w io.Writer) error {
+		return WriteSVG(w, stations, paths)
+	})
+}
+
+// WriteSVG writes an SVG image of the network, with every distinct route in its own color and a legend
+// Parameters:
+//
+//	w: The writer to write the SVG document to
+//	stations: A map of all stations in the network, keyed by station name
+//	paths: A slice of paths, one per train, where a repeated station means the train waits for a turn
+//
+// Returns:
+//
+//	Any error encountered while writing
+func WriteSVG(w io.Writer, stations map[string]*model.Station, paths [][]string) error {
+	// Grow the canvas so that neighbouring stations stay apart on large maps
+	margin := 120
+	width, height := canvasSize(stations, 1000, 800, margin, svgPixelsPerUnit)
+	l := newLayout(stations, width, height, margin)
+
+	routes := distinctRoutes(paths)
+	legendHeight := 40 + 24*len(routes)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\">\n",
+		l.width, l.height+legendHeight, l.width, l.height+legendHeight)
+	b.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+
+	// Draw grid and axes
+	left, right, top, bottom := l.margin, l.width-l.margin, l.margin, l.height-l.margin
+	b.WriteString("<g id=\"grid\" stroke=\"#c8c8c8\" stroke-width=\"1\">\n")
+	for x := left; x <= right; x += l.gridStep() {
+		fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", x, top, x, bottom)
+	}
+	for y := top; y <= bottom; y += l.gridStep() {
+		fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", left, y, right, y)
+	}
+	fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\"/>\n", left, bottom, right, bottom)
+	fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\"/>\n", left, top, left, bottom)
+	b.WriteString("</g>\n")
+
+	// Draw every connection once, in a stable order
+	names := make([]string, 0, len(stations))
+	for name := range stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("<g id=\"connections\" stroke=\"#646464\" stroke-width=\"2\">\n")
+	for _, name := range names {
+		station := stations[name]
+		for _, conn := range station.Connections {
+			if conn.Name < name {
+				continue // Drawn from the other station
+			}
+			x1, y1 := l.station(station)
+			x2, y2 := l.station(conn)
+			fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", x1, y1, x2, y2)
+		}
+	}
+	b.WriteString("</g>\n")
+
+	// Draw routes with different colors
+	b.WriteString("<g id=\"routes\" fill=\"none\" stroke-width=\"4\" stroke-opacity=\"0.8\" stroke-linejoin=\"round\">\n")
+	for i, r := range routes {
+		points := make([]string, 0, len(r.stations))
+		for _, name := range r.stations {
+			x, y := l.station(stations[name])
+			points = append(points, fmt.Sprintf("%d,%d", x, y))
+		}
+		fmt.Fprintf(&b, "<polyline stroke=\"%s\" points=\"%s\"><title>%s</title></polyline>\n",
+			svgColors[i%len(svgColors)], strings.Join(points, " "), html.EscapeString(core.TrainList(r.trains)))
+	}
+	b.WriteString("</g>\n")
+
+	// Draw stations with their names
+	b.WriteString("<g id=\"stations\" font-size=\"14\">\n")
+	for _, name := range names {
+		x, y := l.station(stations[name])
+		fmt.Fprintf(&b, "<circle cx=\"%d\" cy=\"%d\" r=\"6\" fill=\"blue\"/>\n", x, y)
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">%s</text>\n", x+10, y-8, html.EscapeString(name))
+	}
+	b.WriteString("</g>\n")
+
+	// Draw the legend below the plot, one line per route
+	fmt.Fprintf(&b, "<g id=\"legend\" font-size=\"14\">\n<text x=\"%d\" y=\"%d\" font-weight=\"bold\">Routes</text>\n", left, l.height+10)
+	for i, r := range routes {
+		y := l.height + 34 + 24*i
+		fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"4\"/>\n",
+			left, y-5, left+30, y-5, svgColors[i%len(svgColors)])
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">%s: %s</text>\n",
+			left+40, y, html.EscapeString(core.TrainList(r.trains)), html.EscapeString(strings.Join(r.stations, " - ")))
+	}
+	b.WriteString("</g>\n</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// distinctRoutes groups the trains by the stations they pass, in order of first use.
+// Waits are left out, so trains on the same tracks share a route whenever they depart.
+func distinctRoutes(paths [][]string) []route {
+	var routes []route
+	index := make(map[string]int) // Position of each route in routes, keyed by its joined stations
+
+	for trainID, path := range paths {
+		var stations []string
+		for i, name := range path {
+			if i == 0 || name != path[i-1] {
+				stations = append(stations, name)
+			}
+		}
+
+		key := strings.Join(stations, ",")
+		if i, exists := index[key]; exists {
+			routes[i].trains = append(routes[i].trains, trainID)
+			continue
+		}
+		index[key] = len(routes)
+		routes = append(routes, route{stations: stations, trains: []int{trainID}})
+	}
+
+	return routes
+}
@@ -6,7 +6,7 @@ import (
 	"image/color"
 	"image/draw"
 	"image/png"
-	"math"
+	"io"
 	"os"
 	"station/internal/model"
 	"unicode"
@@ -54,39 +54,74 @@ var pixelFont = map[rune][][]bool{
 	'_': {{false, false, false, false}, {false, false, false, false}, {false, false, false, false}, {false, false, false, false}, {true, true, true, true}},
 }
 
-// CreateVisualization generates a PNG image of the network and train paths
-func CreateVisualization(stations map[string]*model.Station, paths [][]string) error {
-	// Define initial canvas size and margins
-	width, height := 1000, 800
-	margin := 50
+// CreateVisualization generates a PNG image of the network and train paths, highlighting the
+// single points of failure of the network when given
+func CreateVisualization(stations map[string]*model.Station, paths [][]string, weak ...model.Resilience) error {
+	return saveFile("network_visualization.png", "Visualization", func(w io.Writer) error {
+		return WritePNG(w, stations, paths, weak...)
+	})
+}
 
-	// Calculate the bounding box of the network
-	maxX, maxY := 0, 0
-	for _, station := range stations {
-		if station.X > maxX {
-			maxX = station.X
-		}
-		if station.Y > maxY {
-			maxY = station.Y
-		}
+// saveFile creates a file, writes it with the given function and reports it as "<kind> saved as <filename>".
+// The report goes to stderr so that machine-readable output on stdout stays intact.
+func saveFile(filename, kind string, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s saved as %s\n", kind, filename)
+	return nil
+}
 
-	// Calculate scaling factor and grid step size based on the bounding box
-	scale := int(math.Min(float64(width-margin*2)/float64(maxX), float64(height-margin*2)/float64(maxY))) - 1
-	gridStep := scale / 2
+// WritePNG writes a PNG image of the network and train paths, as saved by CreateVisualization
+// Parameters:
+//
+//	w: The writer to write the PNG image to
+//	stations: A map of all stations in the network, keyed by station name
+//	paths: A slice of paths, one per train, drawn in alternating colors
+//	weak: Optional articulation points and bridges, drawn as dark red rings and thick dark red lines
+//
+// Returns:
+//
+//	Any error encountered while encoding or writing
+func WritePNG(w io.Writer, stations map[string]*model.Station, paths [][]string, weak ...model.Resilience) error {
+	img, _ := drawNetwork(stations, paths, weak...)
+	return png.Encode(w, img)
+}
+
+// drawNetwork draws the grid, stations, connections and train paths onto a new image
+// Parameters:
+//
+//	stations: A map of all stations in the network, keyed by station name
+//	paths: A slice of paths, one per train, drawn in alternating colors
+//	weak: Optional articulation points and bridges to highlight
+//
+// Returns:
+//
+//	The image, and the layout used to place network coordinates on it
+func drawNetwork(stations map[string]*model.Station, paths [][]string, weak ...model.Resilience) (*image.RGBA, layout) {
+	// Grow the canvas with the map, within the limit that keeps the image in memory
+	margin := 50
+	width, height := canvasSize(stations, 1000, 800, margin, pngPixelsPerUnit)
+	l := newLayout(stations, min(width, pngMaxSize), min(height, pngMaxSize), margin)
+	left, right, top, bottom := l.margin, l.width-l.margin, l.margin, l.height-l.margin
 
 	// Create a new image
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
 	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
 
 	// Draw grid and axes
-	drawGrid(img, margin, width-margin, margin, height-margin, gridStep)
-	drawAxes(img, margin, width-margin, margin, height-margin)
+	drawGrid(img, left, right, top, bottom, l.gridStep())
+	drawAxes(img, left, right, top, bottom)
 
 	// Draw stations
 	for name, station := range stations {
-		x, y := margin+station.X*scale, height-margin-station.Y*scale
-		drawCircle(img, x, y, 5, color.RGBA{0, 0, 255, 255}) // Blue circle for stations
+		x, y := l.station(station)
+		drawCircle(img, x, y, 5, stationColor)
 
 		// Draw station name
 		nameColor := color.RGBA{255, 0, 0, 255} // Red color for station names
@@ -96,41 +131,62 @@ func CreateVisualization(stations map[string]*model.Station, paths [][]string) e
 	// Draw connections between stations
 	for _, station := range stations {
 		for _, conn := range station.Connections {
-			drawLine(img,
-				margin+station.X*scale, height-margin-station.Y*scale,
-				margin+conn.X*scale, height-margin-conn.Y*scale,
-				color.RGBA{100, 100, 100, 255}) // Gray lines for connections
+			x1, y1 := l.station(station)
+			x2, y2 := l.station(conn)
+			drawLine(img, x1, y1, x2, y2, color.RGBA{100, 100, 100, 255}) // Gray lines for connections
 		}
 	}
 
 	// Draw paths with different colors
-	colors := []color.RGBA{
-		{255, 0, 0, 255},   // Red
-		{0, 255, 0, 255},   // Green
-		{255, 165, 0, 255}, // Orange
-		{255, 0, 255, 255}, // Magenta
-	}
 	for i, path := range paths {
-		pathColor := colors[i%len(colors)]
+		pathColor := pathColors[i%len(pathColors)]
 		for j := 1; j < len(path); j++ {
-			start := stations[path[j-1]]
-			end := stations[path[j]]
-			drawLine(img,
-				margin+start.X*scale, height-margin-start.Y*scale,
-				margin+end.X*scale, height-margin-end.Y*scale,
-				pathColor)
+			x1, y1 := l.station(stations[path[j-1]])
+			x2, y2 := l.station(stations[path[j]])
+			drawLine(img, x1, y1, x2, y2, pathColor)
 		}
 	}
 
-	// Save the image
-	f, err := os.Create("network_visualization.png")
-	if err != nil {
-		return err
+	// Highlight single points of failure on top, so that no path hides them
+	for _, resilience := range weak {
+		for _, bridge := range resilience.Bridges {
+			x1, y1 := l.station(stations[bridge[0]])
+			x2, y2 := l.station(stations[bridge[1]])
+			for d := -1; d <= 1; d++ {
+				drawLine(img, x1+d, y1, x2+d, y2, weakColor)
+				drawLine(img, x1, y1+d, x2, y2+d, weakColor)
+			}
+		}
+		for _, name := range resilience.ArticulationPoints {
+			x, y := l.station(stations[name])
+			drawCircle(img, x, y, 9, weakColor)
+			drawCircle(img, x, y, 5, stationColor)
+		}
 	}
-	defer f.Close()
-	png.Encode(f, img)
-	fmt.Println("Visualization saved as network_visualization.png")
-	return nil
+
+	return img, l
+}
+
+// pngPixelsPerUnit is the smallest distance between neighbouring grid points in PNG images and
+// animation frames, so that the canvas grows with large maps instead of squeezing them
+const pngPixelsPerUnit = 40
+
+// pngMaxSize is the largest width and height of PNG images and animation frames in pixels; larger
+// maps are squeezed into it again
+const pngMaxSize = 4000
+
+// stationColor is the color of the circles drawn for stations
+var stationColor = color.RGBA{0, 0, 255, 255} // Blue
+
+// weakColor is the color of the articulation points and bridges highlighted on PNG images
+var weakColor = color.RGBA{139, 0, 0, 255} // Dark red
+
+// pathColors are the colors used for the paths of consecutive trains
+var pathColors = []color.RGBA{
+	{255, 0, 0, 255},   // Red
+	{0, 255, 0, 255},   // Green
+	{255, 165, 0, 255}, // Orange
+	{255, 0, 255, 255}, // Magenta
 }
 
 // drawGrid draws a grid on the image
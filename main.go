@@ -4,30 +4,87 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"station/internal/commands"
 	"station/internal/core"
 	"station/internal/io"
-	"station/internal/pathfinding"
+	"station/internal/model"
+	"station/internal/output"
 	"station/internal/utils"
 	"station/internal/visualization"
+	"station/planner"
 	"strconv"
+	"strings"
 )
 
-type errorString struct {
-	s string
-}
-
-func New(text string) error {
-	return &errorString{text}
-}
-func (e *errorString) Error() string {
-	return e.s
-}
-
 func main() {
+	// Subcommands are dispatched before the flags of the default command are parsed
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "validate":
+			if err := commands.Validate(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "lint":
+			if err := commands.Lint(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "export":
+			if err := commands.Export(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "analyze":
+			if err := commands.Analyze(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "suggest":
+			if err := commands.Suggest(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "resilience":
+			if err := commands.Resilience(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "list-networks":
+			if err := commands.ListNetworks(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		case "serve":
+			if err := commands.Serve(os.Args[2:]); err != nil {
+				printError(err)
+			}
+			return
+		}
+	}
+
 	var visualize bool
 	var help bool
+	var format string
+	var jobSpecs listFlags
+	var jobsFile string
+	var animate string
+	var vizFormat string
+	var networkName string
+	var returnTrains int
+	var closeSpecs listFlags
+	var closuresFile string
 	flag.BoolVar(&visualize, "v", false, "Enable visualization")
 	flag.BoolVar(&help, "h", false, "Show help")
+	flag.StringVar(&format, "format", output.FormatText, "Output format: text, json or csv")
+	flag.Var(&jobSpecs, "job", "Route a group of trains, given as start:end:trains (repeatable)")
+	flag.StringVar(&vizFormat, "viz-format", visualization.FormatPNG, "Visualization format: png or svg")
+	flag.StringVar(&animate, "animate", "", "Write an animated GIF of the simulation to the given file")
+	flag.StringVar(&jobsFile, "jobs", "", "File with one start:end:trains job per line")
+	flag.StringVar(&networkName, "network", "", "Name of the network to use when several contain the stations")
+	flag.IntVar(&returnTrains, "return", 0, "Number of trains running from the end station back to the start station")
+	flag.Var(&closeSpecs, "close", "Close a station or connection for some turns, given as station:from-to or a-b:from-to (repeatable)")
+	flag.StringVar(&closuresFile, "closures", "", "File with one closure per line")
 
 	flag.Usage = func() {}
 
@@ -38,60 +95,178 @@ func main() {
 		return
 	}
 
-	if flag.NArg() != 4 {
+	// With jobs only the map file is given as argument
+	multiJob := len(jobSpecs) > 0 || jobsFile != ""
+	if (!multiJob && flag.NArg() != 4) || (multiJob && flag.NArg() != 1) {
 		fmt.Fprintf(os.Stderr, "%s%s%s\n", utils.Red, utils.ErrIncorrectArgCount, utils.Reset)
 		fmt.Fprintf(os.Stderr, "Use: 'go run main.go -h' for usage information\n")
 		return
 	}
 
+	if format != output.FormatText && format != output.FormatJSON && format != output.FormatCSV {
+		printError(&utils.ValueError{Kind: utils.ErrInvalidFormat, Value: format, Expected: "text, json or csv"})
+		return
+	}
+
+	if vizFormat != visualization.FormatPNG && vizFormat != visualization.FormatSVG {
+		printError(&utils.ValueError{Kind: utils.ErrInvalidVizFormat, Value: vizFormat, Expected: "png or svg"})
+		return
+	}
+
 	args := flag.Args()
 	networkMapFile := args[0]
-	startStationName := args[1]
-	endStationName := args[2]
 
-	numTrains, err := strconv.Atoi(args[3])
-	if err != nil || numTrains <= 0 {
-		fmt.Fprintf(os.Stderr, "%s%s%s\n", utils.Red, utils.ErrInvalidTrainCount, utils.Reset)
-		os.Exit(1)
+	jobs, err := collectJobs(args, jobSpecs, jobsFile)
+	if err != nil {
+		printError(err)
 		return
 	}
 
-	networks, err := io.ReadMap(networkMapFile, startStationName, endStationName)
+	closures, err := collectClosures(closeSpecs, closuresFile)
 	if err != nil {
 		printError(err)
 		return
 	}
 
-	_, selectedNetwork, err := core.FindAppropriateMap(networks, startStationName, endStationName)
+	networks, err := planner.OpenMap(networkMapFile)
 	if err != nil {
-		printError(err)
+		printError(core.ExplainMissingStation(err, jobs[0].Start, jobs[0].End))
 		return
 	}
 
-	if startStationName == endStationName {
-		err := New(utils.ErrSameStartEndStation + "WTF?!?")
+	network, err := selectNetwork(networks, networkName, jobs)
+	if err != nil {
 		printError(err)
 		return
 	}
 
-	paths, occupations, err := pathfinding.FindPaths(startStationName, endStationName, selectedNetwork, numTrains)
+	schedule, err := planner.Plan(network, jobs[0].Start, jobs[0].End, jobs[0].Trains, planner.Options{Jobs: jobs[1:], Return: returnTrains, Closures: closures})
 	if err != nil {
 		printError(err)
 		return
 	}
 
-	_ = occupations
-
 	if visualize {
-		err = visualization.CreateVisualization(selectedNetwork, paths)
-		if err != nil {
+		if err := schedule.Visualize(vizFormat); err != nil {
 			fmt.Fprintf(os.Stderr, "%sError creating visualization: %v%s\n", utils.Red, err, utils.Reset)
 		}
 	}
 
-	pathfinding.SimTrain(paths)
+	if animate != "" {
+		if err := schedule.Animate(animate); err != nil {
+			fmt.Fprintf(os.Stderr, "%sError creating animation: %v%s\n", utils.Red, err, utils.Reset)
+		}
+	}
+
+	switch format {
+	case output.FormatJSON:
+		err = schedule.WriteJSON(os.Stdout)
+	case output.FormatCSV:
+		err = schedule.WriteCSV(os.Stdout)
+	default:
+		err = schedule.WriteText(os.Stdout)
+	}
+	if err != nil {
+		printError(err)
+	}
+
+	// Compare with the plan the trains would follow without the closures
+	if len(closures) > 0 {
+		fmt.Fprintf(os.Stderr, "%sDisruptions: %d turns instead of %d (%+d)%s\n",
+			utils.Yellow, schedule.Turns, schedule.UndisruptedTurns, schedule.Turns-schedule.UndisruptedTurns, utils.Reset)
+	}
+}
+
+// listFlags collects the values of a repeatable flag such as -job
+type listFlags []string
+
+func (l *listFlags) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlags) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// collectJobs returns the jobs of the run: the -job flags followed by the jobs file,
+// or the single job given by the start, end and train count arguments
+func collectJobs(args []string, jobSpecs []string, jobsFile string) ([]model.Job, error) {
+	if len(jobSpecs) == 0 && jobsFile == "" {
+		numTrains, err := strconv.Atoi(args[3])
+		if err != nil || numTrains <= 0 {
+			return nil, &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: args[3]}
+		}
+		return []model.Job{{Start: args[1], End: args[2], Trains: numTrains}}, nil
+	}
+
+	var jobs []model.Job
+	for _, spec := range jobSpecs {
+		job, err := io.ParseJob(spec)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if jobsFile != "" {
+		fileJobs, err := io.ReadJobs(jobsFile)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, fileJobs...)
+	}
+
+	if len(jobs) == 0 {
+		return nil, utils.ErrNoJobs
+	}
+	return jobs, nil
+}
+
+// collectClosures returns the closures of the -close flags followed by those of the closures file
+func collectClosures(closeSpecs []string, closuresFile string) ([]model.Closure, error) {
+	var closures []model.Closure
+	for _, spec := range closeSpecs {
+		closure, err := io.ParseClosure(spec)
+		if err != nil {
+			return nil, err
+		}
+		closures = append(closures, closure)
+	}
+
+	if closuresFile != "" {
+		fileClosures, err := io.ReadClosures(closuresFile)
+		if err != nil {
+			return nil, err
+		}
+		closures = append(closures, fileClosures...)
+	}
+	return closures, nil
+}
+
+// selectNetwork returns the network named by the -network flag, or else the first network in file order
+// that serves all jobs, warning when several networks do
+func selectNetwork(networks map[string]*planner.Network, name string, jobs []model.Job) (*planner.Network, error) {
+	if name != "" {
+		return planner.SelectNamedNetwork(networks, name, jobs...)
+	}
+
+	network, err := planner.SelectNetwork(networks, jobs...)
+	if err != nil {
+		return nil, err
+	}
+	if candidates := planner.Candidates(networks, jobs...); len(candidates) > 1 {
+		names := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			names[i] = fmt.Sprintf("'%s'", candidate.Name)
+		}
+		fmt.Fprintf(os.Stderr, "%sWarning: the stations exist in several networks: %s. Using '%s', choose another with -network%s\n",
+			utils.Yellow, strings.Join(names, ", "), network.Name, utils.Reset)
+	}
+	return network, nil
 }
 
+// printError prints an error in red and exits; errors are only coloured here, at the edge of the CLI
 func printError(err error) {
 	fmt.Fprintf(os.Stderr, "%s%s%s\n", utils.Red, err.Error(), utils.Reset)
 	os.Exit(1)
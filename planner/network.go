@@ -0,0 +1,163 @@
+// Package planner is the public API for loading rail network maps and planning train routes.
+// The command line tool is a thin wrapper around it, so anything the tool can plan can also be
+// planned by importing this package.
+package planner
+
+import (
+	"io"
+	"sort"
+	"station/internal/core"
+	stationio "station/internal/io"
+	"station/internal/model"
+	"station/internal/visualization"
+)
+
+// Network is a single rail network of a map
+type Network struct {
+	Name     string                    // Name of the network, as given in its "--- name ---" header
+	Index    int                       // Position of the network in its map file, starting at 0, or -1 for networks combined through interchanges
+	stations map[string]*model.Station // Stations of the network, keyed by station name
+	source   *model.Map                // The map the network was read from, holding its interchanges
+}
+
+// Stations returns the names of all stations of the network in alphabetical order
+func (n *Network) Stations() []string {
+	names := make([]string, 0, len(n.stations))
+	for name := range n.stations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Connections returns the number of connections between the stations of the network
+func (n *Network) Connections() int {
+	count := 0
+	for _, station := range n.stations {
+		count += len(station.Connections)
+	}
+	return count / 2 // Every connection is listed by both of its stations
+}
+
+// HasStation reports whether the network contains a station with the given name
+func (n *Network) HasStation(name string) bool {
+	_, exists := n.stations[name]
+	return exists
+}
+
+// WritePNG writes a PNG image of the network without any train paths
+func (n *Network) WritePNG(w io.Writer) error {
+	return visualization.WritePNG(w, n.stations, nil)
+}
+
+// LoadMap reads all networks of a map from a reader
+// Parameters:
+//
+//	r: The map contents, either in the .map format or as a GeoJSON FeatureCollection
+//
+// Returns:
+//
+//	A map of network names to networks, and any error encountered.
+//	The format is detected from the first non-blank character: GeoJSON starts with '{'.
+//	GeoJSON features without a network belong to a network named "map".
+func LoadMap(r io.Reader) (map[string]*Network, error) {
+	networks, err := stationio.ParseMap(r)
+	if err != nil {
+		return nil, err
+	}
+	return wrapNetworks(networks), nil
+}
+
+// OpenMap reads all networks of a map file, which is read as GeoJSON if it ends in .geojson or .json.
+// The path "-" reads the map from standard input like LoadMap.
+func OpenMap(path string) (map[string]*Network, error) {
+	networks, err := stationio.ReadMapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return wrapNetworks(networks), nil
+}
+
+// SelectNetwork returns the network that serves all jobs
+// Parameters:
+//
+//	networks: The networks of a map, as returned by LoadMap or OpenMap
+//	jobs: The groups of trains that must run on the network
+//
+// Returns:
+//
+//	The network containing the start and end stations of every job, the first one in file order if
+//	several do (see Candidates), or an error if the stations are missing or spread over different networks.
+//	Networks linked by interchanges are combined when no single network serves all jobs: the stations of
+//	the combined network are named after their network, e.g. "london.st_pancras".
+func SelectNetwork(networks map[string]*Network, jobs ...Job) (*Network, error) {
+	m := unwrapNetworks(networks)
+	name, stations, err := core.FindMapForJobs(m, jobs)
+	if err != nil {
+		return nil, err
+	}
+	if network, exists := networks[name]; exists {
+		return network, nil
+	}
+	return &Network{Name: name, Index: -1, stations: stations, source: m}, nil
+}
+
+// SelectNamedNetwork returns the network with the given name, checking that it serves all jobs
+func SelectNamedNetwork(networks map[string]*Network, name string, jobs ...Job) (*Network, error) {
+	if _, err := core.FindNamedMap(unwrapNetworks(networks), name, jobs); err != nil {
+		return nil, err
+	}
+	return networks[name], nil
+}
+
+// Candidates returns every network that contains the start and end stations of all jobs, in file order.
+// SelectNetwork picks the first one, so more than one candidate means the map is ambiguous for the jobs.
+func Candidates(networks map[string]*Network, jobs ...Job) []*Network {
+	var candidates []*Network
+	for _, name := range core.FindNetworks(unwrapNetworks(networks), jobs) {
+		candidates = append(candidates, networks[name])
+	}
+	return candidates
+}
+
+// Ordered returns the networks in the order they appear in their map file
+func Ordered(networks map[string]*Network) []*Network {
+	ordered := make([]*Network, 0, len(networks))
+	for _, network := range networks {
+		ordered = append(ordered, network)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Index < ordered[j].Index })
+	return ordered
+}
+
+// wrapNetworks turns the map returned by the io package into networks
+func wrapNetworks(m *model.Map) map[string]*Network {
+	result := make(map[string]*Network, len(m.Networks))
+	for i, name := range m.Order {
+		result[name] = &Network{Name: name, Index: i, stations: m.Networks[name], source: m}
+	}
+	return result
+}
+
+// unwrapNetworks turns networks back into the map used by the core package, in file order,
+// keeping the interchanges between them
+func unwrapNetworks(networks map[string]*Network) *model.Map {
+	m := model.NewMap()
+	var source *model.Map
+	for _, network := range Ordered(networks) {
+		m.Networks[network.Name] = network.stations
+		m.Order = append(m.Order, network.Name)
+		if source == nil {
+			source = network.source
+		}
+	}
+
+	if source != nil {
+		for _, interchange := range source.Interchanges {
+			if networks[interchange.Network1] != nil && networks[interchange.Network2] != nil {
+				m.Interchanges = append(m.Interchanges, interchange)
+			}
+		}
+	}
+	return m
+}
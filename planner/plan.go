@@ -0,0 +1,189 @@
+package planner
+
+import (
+	"io"
+	"station/internal/core"
+	"station/internal/model"
+	"station/internal/output"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"station/internal/visualization"
+	"strconv"
+)
+
+// Job is a group of trains travelling between the same start and end stations
+type Job = model.Job
+
+// Train is the movement timeline of a single train
+type Train = output.Train
+
+// Movement is a single hop of a train between two stations
+type Movement = output.Movement
+
+// Closure takes a station or a connection out of service for a window of turns
+type Closure = model.Closure
+
+// Options tunes how Plan routes trains. The zero value plans a single group of trains.
+// New behaviours are added as fields whose zero value keeps the previous behaviour.
+type Options struct {
+	// Jobs are further groups of trains routed over the same network, sharing its tracks and
+	// platforms with the first group. Their trains are numbered after those of the first group.
+	Jobs []Job
+
+	// Return is the number of trains running from the end station back to the start station at the
+	// same time, for an up-and-down service on one line. They are numbered right after the trains
+	// of the first group and before those of Jobs.
+	Return int
+
+	// Closures take stations and connections out of service for some turns. Trains wait for them
+	// to reopen or take another route, and the schedule reports how many turns this costs.
+	Closures []Closure
+}
+
+// Schedule is a planned run of trains over a network
+type Schedule struct {
+	Network string     // Name of the network the trains run on
+	Jobs    []Job      // Groups of trains, the first one being given to Plan directly
+	Turns   int        // Turn in which the last train arrives
+	Paths   [][]string // One path per train, where a repeated station means the train waits for a turn
+	Trains  []Train    // Movement timeline of every train, in the order of Paths
+
+	// UndisruptedTurns is the turn in which the last train would arrive without the closures of
+	// Options, equal to Turns when there are none
+	UndisruptedTurns int
+
+	network  *Network      // Network the schedule was planned on
+	closures []Closure     // Closures the schedule was planned around
+	result   output.Result // Report written by WriteJSON and WriteCSV
+}
+
+// Plan routes trains over a network in the fewest turns
+// Parameters:
+//
+//	network: The network to plan on, as returned by LoadMap, OpenMap or SelectNetwork
+//	start: The name of the station the trains start from. On networks combined through interchanges,
+//	       stations may be given by their own name when it is unique, e.g. "waterloo" for "london.waterloo".
+//	end: The name of the station the trains must reach
+//	trains: The number of trains to route
+//	options: Further behaviour, see Options
+//
+// Returns:
+//
+//	The schedule, or an error if a station does not exist, the number of trains is not positive,
+//	no route connects the stations, or a closure refers to a station or connection that does not exist
+func Plan(network *Network, start, end string, trains int, options Options) (*Schedule, error) {
+	if options.Return < 0 {
+		return nil, &utils.ValueError{Kind: utils.ErrInvalidTrainCount, Value: strconv.Itoa(options.Return)}
+	}
+	jobs := []Job{{Start: start, End: end, Trains: trains}}
+	if options.Return > 0 {
+		jobs = append(jobs, Job{Start: end, End: start, Trains: options.Return})
+	}
+	jobs = core.ResolveJobs(network.stations, append(jobs, options.Jobs...))
+
+	paths, err := findPaths(jobs, network.stations)
+	if err != nil {
+		return nil, err
+	}
+	undisrupted, err := output.NewResult(network.Name, jobs, paths, network.stations)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Closures) == 0 {
+		return newSchedule(network, jobs, undisrupted, paths, nil, undisrupted.Turns), nil
+	}
+
+	// Plan again around the closures, keeping the undisrupted plan for comparison
+	closures := resolveClosures(network.stations, options.Closures)
+	if err := core.CheckClosures(network.stations, closures); err != nil {
+		return nil, err
+	}
+	if paths, err = pathfinding.FindDisruptedPaths(jobs, network.stations, closures); err != nil {
+		return nil, err
+	}
+
+	// The report also checks that the trains can run as planned
+	result, err := output.NewResult(network.Name, jobs, paths, network.stations, closures...)
+	if err != nil {
+		return nil, err
+	}
+	result.Disruption = output.NewDisruption(closures, undisrupted.Turns, result.Turns)
+	return newSchedule(network, jobs, result, paths, closures, undisrupted.Turns), nil
+}
+
+// findPaths routes the trains of all jobs on a network without closures
+func findPaths(jobs []Job, stations map[string]*model.Station) ([][]string, error) {
+	if len(jobs) == 1 {
+		paths, _, err := pathfinding.FindPaths(jobs[0].Start, jobs[0].End, stations, jobs[0].Trains)
+		return paths, err
+	}
+	return pathfinding.FindJobPaths(jobs, stations)
+}
+
+// resolveClosures names the stations of closures like those of jobs, see core.ResolveStation
+func resolveClosures(stations map[string]*model.Station, closures []Closure) []Closure {
+	resolved := make([]Closure, len(closures))
+	for i, closure := range closures {
+		resolved[i] = closure
+		if closure.Station != "" {
+			resolved[i].Station = core.ResolveStation(stations, closure.Station)
+		} else {
+			resolved[i].From = core.ResolveStation(stations, closure.From)
+			resolved[i].To = core.ResolveStation(stations, closure.To)
+		}
+	}
+	return resolved
+}
+
+// newSchedule builds the schedule of a run from its report
+func newSchedule(network *Network, jobs []Job, result output.Result, paths [][]string, closures []Closure, undisruptedTurns int) *Schedule {
+	return &Schedule{
+		Network:          network.Name,
+		Jobs:             jobs,
+		Turns:            result.Turns,
+		Paths:            paths,
+		Trains:           result.Trains,
+		UndisruptedTurns: undisruptedTurns,
+		network:          network,
+		closures:         closures,
+		result:           result,
+	}
+}
+
+// WriteText writes one line per turn listing the trains that moved, e.g. "T1-victoria T2-euston"
+func (s *Schedule) WriteText(w io.Writer) error {
+	sim, err := pathfinding.NewSimulation(s.Paths, s.network.stations, s.closures...)
+	if err != nil {
+		return err
+	}
+	return pathfinding.PrintText(w, sim)
+}
+
+// WriteJSON writes the schedule as an indented JSON document
+func (s *Schedule) WriteJSON(w io.Writer) error {
+	return output.WriteJSON(w, s.result)
+}
+
+// WriteCSV writes the schedule as CSV, one row per train movement
+func (s *Schedule) WriteCSV(w io.Writer) error {
+	return output.WriteCSV(w, s.result)
+}
+
+// Visualize draws the network with the routes of the trains to network_visualization.png,
+// or to network_visualization.svg when format is "svg"
+func (s *Schedule) Visualize(format string) error {
+	if format == visualization.FormatSVG {
+		return visualization.CreateSVGVisualization(s.network.stations, s.Paths)
+	}
+	return visualization.CreateVisualization(s.network.stations, s.Paths)
+}
+
+// WritePNG writes the image saved by Visualize in PNG format to a writer
+func (s *Schedule) WritePNG(w io.Writer) error {
+	return visualization.WritePNG(w, s.network.stations, s.Paths)
+}
+
+// Animate writes an animated GIF of the trains moving turn by turn to the given file
+func (s *Schedule) Animate(filename string) error {
+	return visualization.CreateAnimation(s.network.stations, s.Paths, filename)
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"strings"
+	"testing"
+)
+
+// TestAnalyze checks the routes, minimum cut and minimum turns reported for a pair of stations
+func TestAnalyze(t *testing.T) {
+	networks, err := io.ReadMap("../network.map")
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Beginning to Terminus Map"]
+
+	analysis, err := pathfinding.Analyze("beginning", "terminus", stations, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(analysis.Routes) != "[[beginning terminus] [beginning near far terminus]]" || fmt.Sprint(analysis.Lengths) != "[1 3]" {
+		t.Errorf("Wrong routes: %v %v", analysis.Routes, analysis.Lengths)
+	}
+
+	// The direct track cannot be avoided, the other route is cut at a station rather than a track
+	if fmt.Sprint(analysis.CutStations) != "[near]" || fmt.Sprint(analysis.CutTracks) != "[[beginning terminus]]" {
+		t.Errorf("Wrong minimum cut: %v %v", analysis.CutStations, analysis.CutTracks)
+	}
+	if analysis.MinTurns != 11 {
+		t.Errorf("Wanted 11 turns for 20 trains, got %d", analysis.MinTurns)
+	}
+
+	// Platforms let more routes through a station, so the cut moves elsewhere
+	networks, err = io.Parse(strings.NewReader(strings.Replace(lineMap, "e,1,1", "e,1,1,2", 1)))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	analysis, err = pathfinding.Analyze("b", "c", networks["Line"], 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(analysis.Routes) != 2 || fmt.Sprint(analysis.CutStations) != "[]" || len(analysis.CutTracks) != 2 || analysis.MinTurns != 3 {
+		t.Errorf("Wrong analysis: %+v", analysis)
+	}
+
+	if _, err := pathfinding.Analyze("beginning", "nowhere", stations, 1); !errors.Is(err, utils.ErrEndStationNotExist) {
+		t.Errorf("Expected a missing end station, got %v", err)
+	}
+}
+
+// TestAnalyzeCommand checks the report of the analyze subcommand
+func TestAnalyzeCommand(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "analyze", "network.map", "beginning", "terminus", "20")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	expected := `Network: Beginning to Terminus Map
+Maximum disjoint routes: 2
+  beginning-terminus (1 turn(s))
+  beginning-near-far-terminus (3 turn(s))
+Minimum cut: near, track beginning-terminus
+Minimum turns for 20 train(s): 11
+`
+	if string(output) != expected {
+		t.Errorf("Wanted:\n%s\ngot:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"image/gif"
+	"os"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/visualization"
+	"testing"
+)
+
+// TestCreateAnimation checks that the GIF has a frame for the start and one per turn
+func TestCreateAnimation(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+
+	paths, _, err := pathfinding.FindPaths("waterloo", "st_pancras", stations, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sim, err := pathfinding.NewSimulation(paths, stations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gifPath := filepath.Join(t.TempDir(), "out.gif")
+	if err := visualization.CreateAnimation(stations, paths, gifPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	f, err := os.Open(gifPath)
+	if err != nil {
+		t.Fatalf("Failed to open animation: %v", err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("Animation is not a valid GIF: %v", err)
+	}
+
+	if len(anim.Image) != sim.Turns()+1 {
+		t.Errorf("Wanted %d frames, got %d", sim.Turns()+1, len(anim.Image))
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// lineMap is a single track line from a to d with a passing loop through e
+const lineMap = `--- Line ---
+stations:
+a,0,0
+b,1,0
+c,2,0
+d,3,0
+e,1,1
+
+connections:
+a-b
+b-c
+c-d
+b-e
+e-c
+`
+
+// TestBidirectionalPlan checks planning trains in both directions on the same line
+func TestBidirectionalPlan(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+
+	schedule, err := planner.Plan(networks["Line"], "a", "d", 3, planner.Options{Return: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(schedule.Paths) != 5 || len(schedule.Jobs) != 2 {
+		t.Fatalf("Wanted 5 trains in 2 jobs, got %d in %v", len(schedule.Paths), schedule.Jobs)
+	}
+
+	// The first trains run up the line and the following ones back down
+	for i, path := range schedule.Paths {
+		start, end := "a", "d"
+		if i >= 3 {
+			start, end = "d", "a"
+		}
+		if path[0] != start || path[len(path)-1] != end {
+			t.Errorf("T%d runs from %s to %s, wanted %s to %s", i+1, path[0], path[len(path)-1], start, end)
+		}
+	}
+
+	m, err := io.Parse(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	if conflicts := core.FindConflicts(schedule.Paths, m["Line"]); len(conflicts) > 0 {
+		t.Errorf("Planned schedule has conflicts: %v", conflicts)
+	}
+
+	if _, err := planner.Plan(networks["Line"], "a", "d", 3, planner.Options{Return: -1}); !errors.Is(err, utils.ErrInvalidTrainCount) {
+		t.Errorf("Expected an invalid train count, got %v", err)
+	}
+}
+
+// TestDeadlock checks that opposing trains waiting for each other on a single track are reported
+func TestDeadlock(t *testing.T) {
+	networks, err := io.Parse(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	stations := networks["Line"]
+
+	// Both trains stop in front of each other at b and c, then meet head-on
+	paths := [][]string{{"a", "b", "b", "c", "d"}, {"d", "c", "c", "b", "a"}}
+	deadlocks := core.FindDeadlocks(paths, stations)
+	if len(deadlocks) != 1 || deadlocks[0].Turn != 2 || deadlocks[0].TrainID != 0 || !strings.Contains(deadlocks[0].Message, "deadlock with T2") {
+		t.Errorf("Expected a deadlock of T1 and T2 in turn 2, got %v", deadlocks)
+	}
+
+	_, err = pathfinding.NewSimulation(paths, stations)
+	var conflict *utils.ConflictError
+	if !errors.As(err, &conflict) || conflict.Turn != 2 || !strings.Contains(conflict.Message, "deadlock") {
+		t.Errorf("Expected the simulation to refuse the deadlock, got %v", err)
+	}
+
+	// A train that can move into the passing loop is not deadlocked
+	paths = [][]string{{"a", "b", "b", "e", "c", "d"}, {"d", "c", "c", "b", "a"}}
+	if deadlocks := core.FindDeadlocks(paths, stations); len(deadlocks) > 0 {
+		t.Errorf("Expected no deadlock, got %v", deadlocks)
+	}
+}
+
+// TestReturnFlag checks the -return flag of the CLI
+func TestReturnFlag(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "-return", "1", writeMap(t, lineMap), "a", "d", "1")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if !strings.Contains(lines[len(lines)-1], "T2-a") || !strings.Contains(string(output), "T1-d") {
+		t.Errorf("Expected T1 to reach d and T2 to reach a, got:\n%s", output)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseClosure checks the closure format of the -close flag and of disruptions files
+func TestParseClosure(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected model.Closure
+		valid    bool
+	}{
+		{"euston:3-7", model.Closure{Station: "euston", Start: 3, End: 7}, true},
+		{"euston-st_pancras:2-4", model.Closure{From: "euston", To: "st_pancras", Start: 2, End: 4}, true},
+		{" euston : 5 ", model.Closure{Station: "euston", Start: 5, End: 5}, true},
+		{"euston", model.Closure{}, false},
+		{"euston:7-3", model.Closure{}, false},
+		{"euston:0-3", model.Closure{}, false},
+		{"-euston:1-2", model.Closure{}, false},
+		{":1-2", model.Closure{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			closure, err := io.ParseClosure(tc.spec)
+			if !tc.valid {
+				if !errors.Is(err, utils.ErrInvalidClosure) {
+					t.Errorf("Expected an invalid closure, got %v, %v", closure, err)
+				}
+				return
+			}
+			if err != nil || closure != tc.expected {
+				t.Errorf("Wanted %+v, got %+v, %v", tc.expected, closure, err)
+			}
+		})
+	}
+}
+
+// TestClosurePlan checks that trains reroute around or wait for closures, and that the delay is reported
+func TestClosurePlan(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	network := networks["Line"]
+
+	testCases := []struct {
+		name     string
+		closures []planner.Closure
+		turns    int
+		path     string
+	}{
+		{"reroute", []planner.Closure{{From: "b", To: "c", Start: 1, End: 5}}, 4, "a b e c d"},
+		{"wait", []planner.Closure{{Station: "b", Start: 1, End: 3}}, 6, "a a a a b c d"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := planner.Plan(network, "a", "d", 1, planner.Options{Closures: tc.closures})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if schedule.Turns != tc.turns || schedule.UndisruptedTurns != 3 || strings.Join(schedule.Paths[0], " ") != tc.path {
+				t.Errorf("Wanted %s in %d turns instead of 3, got %v in %d turns instead of %d",
+					tc.path, tc.turns, schedule.Paths[0], schedule.Turns, schedule.UndisruptedTurns)
+			}
+
+			var buf bytes.Buffer
+			if err := schedule.WriteJSON(&buf); err != nil {
+				t.Fatalf("Failed to write JSON: %v", err)
+			}
+			var result struct {
+				Disruption struct {
+					Delta int `json:"delta"`
+				} `json:"disruption"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &result); err != nil || result.Disruption.Delta != tc.turns-3 {
+				t.Errorf("Wanted a delta of %d turns, got %s", tc.turns-3, buf.String())
+			}
+		})
+	}
+
+	if _, err := planner.Plan(network, "a", "d", 1, planner.Options{Closures: []planner.Closure{{From: "a", To: "d", Start: 1, End: 2}}}); !errors.Is(err, utils.ErrInvalidClosure) {
+		t.Errorf("Expected a closure of a missing connection to be rejected, got %v", err)
+	}
+}
+
+// TestLongClosure checks that the planning time does not grow with the square of a closure's length
+func TestLongClosure(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+
+	began := time.Now()
+	schedule, err := planner.Plan(networks["Line"], "a", "d", 2, planner.Options{Closures: []planner.Closure{{Station: "a", Start: 1, End: 100000}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.Turns != 100004 || schedule.UndisruptedTurns != 4 {
+		t.Errorf("Wanted the trains to arrive in turn 100004 instead of 4, got %d instead of %d", schedule.Turns, schedule.UndisruptedTurns)
+	}
+	if elapsed := time.Since(began); elapsed > 5*time.Second {
+		t.Errorf("Planning around a closure of 100000 turns took %v", elapsed)
+	}
+}
+
+// TestClosureSimulation checks that the simulator refuses trains travelling through a closure
+func TestClosureSimulation(t *testing.T) {
+	networks, err := io.Parse(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	stations := networks["Line"]
+	closures := []model.Closure{{Station: "c", Start: 2, End: 2}}
+
+	paths := [][]string{{"a", "b", "c", "d"}}
+	if conflicts := core.FindClosureConflicts(paths, stations, closures); len(conflicts) != 1 || conflicts[0].Turn != 2 {
+		t.Errorf("Expected a closure conflict in turn 2, got %v", conflicts)
+	}
+	var conflict *utils.ConflictError
+	if _, err := pathfinding.NewSimulation(paths, stations, closures...); !errors.As(err, &conflict) || conflict.Turn != 2 {
+		t.Errorf("Expected the simulation to refuse the closed station, got %v", err)
+	}
+
+	// Waiting at b while c is closed is allowed
+	if _, err := pathfinding.NewSimulation([][]string{{"a", "b", "b", "c", "d"}}, stations, closures...); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestCloseFlag checks the -close flag of the CLI and its report of the delay
+func TestCloseFlag(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "-close", "b-c:1-5", writeMap(t, lineMap), "a", "d", "1")
+	cmd.Dir = filepath.Dir(mainPath)
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, stderr.String())
+	}
+
+	if strings.TrimSpace(stdout.String()) != "T1-b\nT1-e\nT1-c\nT1-d" {
+		t.Errorf("Expected T1 to reroute through e, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "4 turns instead of 3 (+1)") {
+		t.Errorf("Expected the delay to be reported, got:\n%s", stderr.String())
+	}
+}
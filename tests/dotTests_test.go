@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/output"
+	"strings"
+	"testing"
+)
+
+// TestWriteDOT checks positions, track labels and highlighted train paths in the DOT graph
+func TestWriteDOT(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	paths := [][]string{
+		{"waterloo", "victoria", "st_pancras"},
+		{"waterloo", "waterloo", "euston", "st_pancras"},
+	}
+
+	var buf bytes.Buffer
+	if err := output.WriteDOT(&buf, "Weighted Map", networks["Weighted Map"], paths); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dot := buf.String()
+
+	for _, want := range []string{
+		`graph "Weighted Map" {`,
+		`"euston" [pos="11,23!"];`,
+		`"victoria" -- "waterloo" [label="duration=3"];`,
+		`"waterloo" -- "victoria" [color=red, penwidth=2, label="T1", fontcolor=red];`,
+		`"euston" -- "st_pancras" [color=blue, penwidth=2, label="T2", fontcolor=blue];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT graph does not contain '%s':\n%s", want, dot)
+		}
+	}
+	if strings.Contains(dot, `"waterloo" -- "waterloo"`) {
+		t.Errorf("Waiting trains should not add edges:\n%s", dot)
+	}
+}
+
+// TestExportDOTCommand checks that the export command writes one graph per network
+func TestExportDOTCommand(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := filepath.Join(filepath.Dir(mainPath), "network.map")
+	dotPath := filepath.Join(t.TempDir(), "network.dot")
+
+	cmd := exec.Command("go", "run", mainPath, "export", "-o", dotPath, "dot", mapPath, "waterloo", "st_pancras", "4")
+	if outputBytes, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Unexpected error: %v\n%s", err, outputBytes)
+	}
+
+	contents, err := os.ReadFile(dotPath)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	dot := string(contents)
+	if !strings.Contains(dot, `graph "London Network Map" {`) || !strings.Contains(dot, `label="T4"`) {
+		t.Errorf("Export is missing the London network or its train paths:\n%s", dot)
+	}
+	if strings.Count(dot, "graph ") < 2 {
+		t.Errorf("Wanted a graph for every network of the map")
+	}
+}
@@ -1,9 +1,14 @@
 package tests
 
 import (
+	"errors"
+	"io/fs"
 	"os/exec"
 	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
 	"station/internal/utils"
+	"station/planner"
 	"strconv"
 	"strings"
 	"testing"
@@ -19,13 +24,23 @@ func findMainGo() (string, error) {
 	return mainPath, nil
 }
 
-// TestErrorCases tests various error scenarios
-func TestErrorCases(t *testing.T) {
-	mainPath, err := findMainGo()
+// planRoute reads a map and routes trains through it the way the CLI does, with the public
+// planner package, returning the first error encountered
+func planRoute(mapPath, start, end string, numTrains int) error {
+	networks, err := planner.OpenMap(mapPath)
 	if err != nil {
-		t.Fatalf("Failed to find main.go: %v", err)
+		return core.ExplainMissingStation(err, start, end)
 	}
+	network, err := planner.SelectNetwork(networks, planner.Job{Start: start, End: end, Trains: numTrains})
+	if err != nil {
+		return err
+	}
+	_, err = planner.Plan(network, start, end, numTrains, planner.Options{})
+	return err
+}
 
+// TestErrorCases tests that each broken input is rejected with the expected kind of error
+func TestErrorCases(t *testing.T) {
 	// Get the absolute path to the tests directory
 	testsDir, err := filepath.Abs(".")
 	if err != nil {
@@ -37,44 +52,105 @@ func TestErrorCases(t *testing.T) {
 		startStation   string
 		endStation     string
 		numberOfTrains int
-		expectedError  string
+		expectedError  error
 	}{
-		{"10no-start-station_london.txt", "waterloo", "st_pancras", 2, "Error: Start station does not exist"},
-		{"11no-end-station_london.txt", "waterloo", "st_pancras", 2, "Error: End station does not exist"},
-		{"12same-start-end_london.txt", "waterloo", "waterloo", 2, "Error: Start and end station are the same"},
-		{"13no-path_london.txt", "waterloo", "st_pancras", 2, "Error: no paths found"},
-		{"14duplicate-routes_london.txt", "waterloo", "st_pancras", 2, "Error: Duplicate connection between"},
-		{"16no-valid-coord_london.txt", "waterloo", "st_pancras", 2, "Error: Invalid x coordinate for station"},
-		{"17same-coords_london.txt", "waterloo", "st_pancras", 2, "Error: Two stations exist at the same coordinates"},
-		{"18station-not-exist_london.txt", "waterloo", "st_pancras", 2, "Error: Connetion to non existing station"},
-		{"19duplicate-names_london.txt", "waterloo", "st_pancras", 2, "Error: Duplicate station names"},
-		{"21no-stations_london.txt", "waterloo", "st_pancras", 2, "Error: Network 'London Network Map' does not contain a 'stations:' section"},
-		{"22no-connections_london.txt", "waterloo", "st_pancras", 2, "Error: Network 'London Network Map' does not contain a 'connections:' section"},
-		{"23over-tenK.txt", "station1", "station10001", 2, "Error: Map contains more than 10000 stations"},
-		{"invalidname_london.txt", "waterloo", "st_pancras", 2, "Error: Invalid station name in network"},
-		{"network.map", "waterloo", "st_pancras", -2, "Error: Number of trains is not a valid positive integer"},
+		{"10no-start-station_london.txt", "waterloo", "st_pancras", 2, utils.ErrStartStationNotExist},
+		{"11no-end-station_london.txt", "waterloo", "st_pancras", 2, utils.ErrEndStationNotExist},
+		{"12same-start-end_london.txt", "waterloo", "waterloo", 2, utils.ErrSameStartEndStation},
+		{"13no-path_london.txt", "waterloo", "st_pancras", 2, utils.ErrNoPath},
+		{"14duplicate-routes_london.txt", "waterloo", "st_pancras", 2, utils.ErrDuplicateConnections},
+		{"16no-valid-coord_london.txt", "waterloo", "st_pancras", 2, utils.ErrInvalidCoordinates},
+		{"17same-coords_london.txt", "waterloo", "st_pancras", 2, utils.ErrSameCoordinates},
+		{"18station-not-exist_london.txt", "waterloo", "st_pancras", 2, utils.ErrStationDoesNotExistInConnections},
+		{"19duplicate-names_london.txt", "waterloo", "st_pancras", 2, utils.ErrDuplicateStationNames},
+		{"21no-stations_london.txt", "waterloo", "st_pancras", 2, utils.ErrNoStationsSection},
+		{"22no-connections_london.txt", "waterloo", "st_pancras", 2, utils.ErrNoConnectionsSection},
+		{"23over-tenK.txt", "station1", "station10001", 2, utils.ErrTooManyStations},
+		{"invalidname_london.txt", "waterloo", "st_pancras", 2, utils.ErrInvalidStationNames},
+		{"../../network.map", "waterloo", "st_pancras", -2, utils.ErrInvalidTrainCount},
 	}
 
 	for _, tc := range errorTestCases {
-		testName := tc.mapFile
+		testName := filepath.Base(tc.mapFile)
 		t.Run(testName, func(t *testing.T) {
-			var mapPath string
-			if tc.mapFile == "network.map" {
-				mapPath = filepath.Join(testsDir, tc.mapFile)
-			} else {
-				mapPath = filepath.Join(testsDir, tc.mapFile)
-			}
-
-			cmd := exec.Command("go", "run", mainPath, mapPath, tc.startStation, tc.endStation, strconv.Itoa(tc.numberOfTrains))
-			output, err := cmd.CombinedOutput()
+			mapPath := filepath.Join(testsDir, tc.mapFile)
+			err := planRoute(mapPath, tc.startStation, tc.endStation, tc.numberOfTrains)
 
 			if err == nil {
-				t.Errorf("%sFAILED: %s - Expected an error, but got none, output is: %s%s", utils.Red, testName, output, utils.Reset)
-			} else if !strings.Contains(string(output), tc.expectedError) {
-				t.Errorf("%sFAILED: %s - Expected error containing '%s', but got: %s%s", utils.Red, testName, tc.expectedError, string(output), utils.Reset)
+				t.Errorf("%sFAILED: %s - Expected an error, but got none%s", utils.Red, testName, utils.Reset)
+			} else if !errors.Is(err, tc.expectedError) {
+				t.Errorf("%sFAILED: %s - Expected error '%v', but got: %v%s", utils.Red, testName, tc.expectedError, err, utils.Reset)
 			} else {
-				t.Logf("%sPASSED: %s - Got expected error: %s%s", utils.Green, testName, strings.TrimSpace(string(output)), utils.Reset)
+				t.Logf("%sPASSED: %s - Got expected error: %v%s", utils.Green, testName, err, utils.Reset)
 			}
 		})
 	}
 }
+
+// TestErrorDetails tests that structured errors carry the station, network and line involved
+func TestErrorDetails(t *testing.T) {
+	testsDir, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	// The x coordinate of waterloo is negative
+	err = planRoute(filepath.Join(testsDir, "16no-valid-coord_london.txt"), "waterloo", "st_pancras", 2)
+	var lineErr *utils.LineError
+	if !errors.As(err, &lineErr) {
+		t.Fatalf("Expected a LineError, got %v", err)
+	}
+	if lineErr.Network != "London Network Map" || lineErr.Line != 3 {
+		t.Errorf("Expected network 'London Network Map' at line 3, got network '%s' at line %d", lineErr.Network, lineErr.Line)
+	}
+	var stationErr *utils.StationError
+	if !errors.As(err, &stationErr) || stationErr.Station != "waterloo" {
+		t.Errorf("Expected a StationError for waterloo, got %v", err)
+	}
+
+	// The start station is not in the map
+	err = planRoute(filepath.Join(testsDir, "10no-start-station_london.txt"), "waterloo", "st_pancras", 2)
+	if !errors.As(err, &stationErr) || stationErr.Station != "waterloo" {
+		t.Errorf("Expected a StationError for waterloo, got %v", err)
+	}
+
+	// The number of trains is rejected with its value
+	err = planRoute(filepath.Join(testsDir, "..", "..", "network.map"), "waterloo", "st_pancras", -2)
+	var valueErr *utils.ValueError
+	if !errors.As(err, &valueErr) || valueErr.Value != "-2" {
+		t.Errorf("Expected a ValueError for '-2', got %v", err)
+	}
+
+	// A missing map file keeps the underlying error, for the planner and the linter alike
+	missing := filepath.Join(testsDir, "missing.map")
+	if _, err := io.ReadMapFile(missing); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist when reading, got %v", err)
+	}
+	if _, err := io.LintMap(missing); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist when linting, got %v", err)
+	}
+}
+
+// TestErrorExitCode tests that the CLI reports errors with a non-zero exit status
+func TestErrorExitCode(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	testsDir, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	mapPath := filepath.Join(testsDir, "10no-start-station_london.txt")
+	cmd := exec.Command("go", "run", mainPath, mapPath, "waterloo", "st_pancras", strconv.Itoa(2))
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit status 1, got %v, output is: %s", err, output)
+	}
+	if !strings.Contains(string(output), utils.ErrStartStationNotExist.Error()) {
+		t.Errorf("Expected the error to be printed, got: %s", output)
+	}
+}
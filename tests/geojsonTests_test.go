@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// writeGeoJSON writes GeoJSON contents to a temporary .geojson file and returns its path
+func writeGeoJSON(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.geojson")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write GeoJSON: %v", err)
+	}
+	return path
+}
+
+// TestGeoJSONRoundTrip checks that an exported network reads back unchanged
+func TestGeoJSONRoundTrip(t *testing.T) {
+	networks, err := io.ReadMapFile(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := io.WriteGeoJSON(&buf, networks); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	imported, err := io.ReadMap(writeGeoJSON(t, buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to read GeoJSON: %v", err)
+	}
+
+	original, stations := networks.Networks["Weighted Map"], imported["Weighted Map"]
+	if len(stations) != len(original) {
+		t.Fatalf("Wanted %d stations, got %d", len(original), len(stations))
+	}
+	for name, want := range original {
+		got := stations[name]
+		if got == nil || got.X != want.X || got.Y != want.Y || len(got.Connections) != len(want.Connections) {
+			t.Errorf("Station %s differs after the round trip: %+v", name, got)
+			continue
+		}
+		for _, conn := range want.Connections {
+			if got.TrackTo(conn.Name) != want.TrackTo(conn.Name) {
+				t.Errorf("Track %s-%s differs after the round trip", name, conn.Name)
+			}
+		}
+	}
+}
+
+// TestGeoJSONInterchanges checks that interchanges are exported and read back, so that trains can still
+// change networks on the imported map
+func TestGeoJSONInterchanges(t *testing.T) {
+	networks, err := io.ReadMapFile(writeMap(t, interchangeMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := io.WriteGeoJSON(&buf, networks); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	exported := buf.String()
+
+	imported, err := io.ReadMapFile(writeGeoJSON(t, exported))
+	if err != nil {
+		t.Fatalf("Failed to read GeoJSON: %v", err)
+	}
+	if fmt.Sprint(imported.Order) != fmt.Sprint(networks.Order) {
+		t.Errorf("Wanted the networks %v, got %v", networks.Order, imported.Order)
+	}
+	if len(imported.Interchanges) != 1 {
+		t.Fatalf("Wanted the interchange back, got %+v", imported.Interchanges)
+	}
+	got, want := imported.Interchanges[0], networks.Interchanges[0]
+	if got.Network1 != want.Network1 || got.Station1 != want.Station1 || got.Network2 != want.Network2 ||
+		got.Station2 != want.Station2 || *got.Track != *want.Track {
+		t.Errorf("Wanted the interchange %+v, got %+v", want, got)
+	}
+
+	// The imported map routes trains across the interchange
+	geoPath := writeGeoJSON(t, exported)
+	loaded, err := planner.OpenMap(geoPath)
+	if err != nil {
+		t.Fatalf("Failed to open GeoJSON: %v", err)
+	}
+	network, err := planner.SelectNetwork(loaded, planner.Job{Start: "waterloo", End: "gare_de_lyon", Trains: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schedule, err := planner.Plan(network, "waterloo", "gare_de_lyon", 1, planner.Options{})
+	if err != nil || schedule.Turns != 6 {
+		t.Errorf("Wanted 6 turns across the interchange, got %d, %v", schedule.Turns, err)
+	}
+
+	testCases := []struct {
+		name     string
+		old, new string
+		expected error
+	}{
+		{"missing station", `"from": "london.st_pancras"`, `"from": "london.euston"`, utils.ErrStationDoesNotExistInConnections},
+		{"unknown network", `"to": "paris.gare_du_nord"`, `"to": "berlin.gare_du_nord"`, utils.ErrNetworkNotExist},
+		{"same network", `"to": "paris.gare_du_nord"`, `"to": "london.waterloo"`, utils.ErrInvalidInterchange},
+		{"unqualified station", `"from": "london.st_pancras"`, `"from": "st_pancras"`, utils.ErrInvalidInterchange},
+		{"missing from", `"from": "london.st_pancras",`, ``, utils.ErrInvalidGeoJSON},
+		{"negative duration", `"duration": 2,
+        "capacity": 1,
+        "interchange": true`, `"duration": -2,
+        "interchange": true`, utils.ErrInvalidTrack},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(exported, tc.old) {
+				t.Fatalf("The export lacks %q:\n%s", tc.old, exported)
+			}
+			_, err := io.ReadMapFile(writeGeoJSON(t, strings.Replace(exported, tc.old, tc.new, 1)))
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Wanted error '%v', got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// TestGeoJSONImport checks connections given by coordinates and the shared map validations
+func TestGeoJSONImport(t *testing.T) {
+	const collection = `{
+  "type": "FeatureCollection",
+  "name": "GIS Map",
+  "features": [
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"name": "alpha"}},
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [2, 1]}, "properties": {"name": "beta", "platforms": 2}},
+    {"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1], [2, 1]]}, "properties": {"duration": 2}}
+  ]
+}`
+
+	networks, err := io.ReadMap(writeGeoJSON(t, collection))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stations := networks["GIS Map"]
+	if stations == nil || stations["beta"].Platforms != 2 || stations["alpha"].TrackTo("beta").Duration != 2 {
+		t.Errorf("Wrong network imported: %v", networks)
+	}
+
+	testCases := []struct {
+		name     string
+		old, new string
+		expected error
+	}{
+		{"duplicate names", `"name": "beta"`, `"name": "alpha"`, utils.ErrDuplicateStationNames},
+		{"same coordinates", `[2, 1]}`, `[0, 0]}`, utils.ErrSameCoordinates},
+		{"invalid name", `"name": "beta"`, `"name": "Beta!"`, utils.ErrInvalidStationNames},
+		{"coordinate before origin", `"name": "GIS Map",`, `"name": "GIS Map", "origin": [1, 0],`, utils.ErrInvalidCoordinates},
+		{"unknown endpoint", `[2, 1]]}`, `[5, 5]]}`, utils.ErrStationDoesNotExistInConnections},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := io.ReadMap(writeGeoJSON(t, strings.Replace(collection, tc.old, tc.new, 1)))
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Wanted error '%v', got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// TestGeoJSONLonLat checks that WGS84 longitudes and latitudes are projected onto the grid
+func TestGeoJSONLonLat(t *testing.T) {
+	const collection = `{
+  "type": "FeatureCollection",
+  "name": "London",
+  "features": [
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [-0.1246, 51.5308]}, "properties": {"name": "kings_cross"}},
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [-0.1337, 51.5282]}, "properties": {"name": "euston"}},
+    {"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[-0.1337, 51.5282], [-0.1246, 51.5308]]}, "properties": {}}
+  ]
+}`
+
+	testCases := []struct {
+		name     string
+		old, new string
+		x, y     int
+	}{
+		{"bounding box", "", "", 100, 29},
+		{"scale", `"name": "London",`, `"name": "London", "scale": 1000,`, 9, 3},
+		{"scale and origin", `"name": "London",`, `"name": "London", "scale": 1000, "origin": [-0.2, 51.5],`, 75, 31},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			networks, err := io.ReadMap(writeGeoJSON(t, strings.Replace(collection, tc.old, tc.new, 1)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			stations := networks["London"]
+			if kingsCross := stations["kings_cross"]; kingsCross.X != tc.x || kingsCross.Y != tc.y {
+				t.Errorf("Wanted kings_cross at %d,%d, got %d,%d", tc.x, tc.y, kingsCross.X, kingsCross.Y)
+			}
+			if len(stations["euston"].Connections) != 1 {
+				t.Errorf("Expected the LineString to connect euston and kings_cross")
+			}
+		})
+	}
+
+	// Stations that fall on the same grid position are rejected as in .map files
+	_, err := io.ReadMap(writeGeoJSON(t, strings.Replace(collection, `"name": "London",`, `"name": "London", "scale": 10,`, 1)))
+	if !errors.Is(err, utils.ErrSameCoordinates) {
+		t.Errorf("Wanted error '%v', got %v", utils.ErrSameCoordinates, err)
+	}
+
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	cmd := exec.Command("go", "run", mainPath, writeGeoJSON(t, collection), "euston", "kings_cross", "2")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil || strings.TrimSpace(string(output)) != "T1-kings_cross\nT2-kings_cross" {
+		t.Errorf("Expected the trains to be planned, got %v:\n%s", err, output)
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// interchangeMap has two networks linked by an interchange declared before the second network
+const interchangeMap = `--- London ---
+stations:
+waterloo,0,0
+victoria,1,0
+st_pancras,2,0
+
+connections:
+waterloo-victoria
+victoria-st_pancras
+
+interchanges:
+london.st_pancras-paris.gare_du_nord 2
+
+--- Paris ---
+stations:
+gare_du_nord,0,0
+chatelet,0,1
+gare_de_lyon,0,2
+
+connections:
+gare_du_nord-chatelet
+chatelet-gare_de_lyon
+`
+
+// TestInterchangeParse checks reading interchanges and rejecting broken ones
+func TestInterchangeParse(t *testing.T) {
+	m, err := io.ParseMap(strings.NewReader(interchangeMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	if len(m.Interchanges) != 1 {
+		t.Fatalf("Wanted 1 interchange, got %v", m.Interchanges)
+	}
+	interchange := m.Interchanges[0]
+	if interchange.Network1 != "London" || interchange.Station1 != "st_pancras" || interchange.Network2 != "Paris" ||
+		interchange.Station2 != "gare_du_nord" || interchange.Track.Duration != 2 {
+		t.Errorf("Wrong interchange: %+v", interchange)
+	}
+
+	testCases := []struct {
+		line     string
+		expected error
+	}{
+		{"london.st_pancras-rome.termini", utils.ErrNetworkNotExist},
+		{"london.st_pancras-paris.nord", utils.ErrStationDoesNotExistInConnections},
+		{"london.st_pancras-london.waterloo", utils.ErrInvalidInterchange},
+		{"st_pancras-paris.gare_du_nord", utils.ErrInvalidInterchange},
+		{"london.st_pancras-paris.gare_du_nord 0", utils.ErrInvalidTrack},
+		{"paris.gare_du_nord-london.st_pancras\nlondon.st_pancras-paris.gare_du_nord", utils.ErrDuplicateConnections},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			contents := strings.Replace(interchangeMap, "london.st_pancras-paris.gare_du_nord 2", tc.line, 1)
+			_, err := io.ParseMap(strings.NewReader(contents))
+
+			var lineErr *utils.LineError
+			if !errors.Is(err, tc.expected) || !errors.As(err, &lineErr) || lineErr.Network != "London" {
+				t.Errorf("Expected %v in network London, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// TestInterchangePlan checks planning trains across networks with the public API
+func TestInterchangePlan(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(interchangeMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+
+	job := planner.Job{Start: "waterloo", End: "gare_de_lyon", Trains: 2}
+	network, err := planner.SelectNetwork(networks, job)
+	if err != nil {
+		t.Fatalf("Failed to select network: %v", err)
+	}
+	if network.Name != "London + Paris" || !network.HasStation("paris.gare_du_nord") || len(network.Stations()) != 6 {
+		t.Errorf("Wrong combined network: %s %v", network.Name, network.Stations())
+	}
+
+	schedule, err := planner.Plan(network, job.Start, job.End, job.Trains, planner.Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "london.waterloo london.victoria london.st_pancras paris.gare_du_nord paris.chatelet paris.gare_de_lyon"
+	if schedule.Turns != 7 || strings.Join(schedule.Paths[0], " ") != expected {
+		t.Errorf("Wanted the trains to cross the interchange in 7 turns, got %d turns along %v", schedule.Turns, schedule.Paths)
+	}
+
+	// Without the interchange the stations are in different networks again
+	networks, err = planner.LoadMap(strings.NewReader(strings.Replace(interchangeMap, "london.st_pancras-paris.gare_du_nord 2", "", 1)))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	if _, err := planner.SelectNetwork(networks, job); !errors.Is(err, utils.ErrNoPath) {
+		t.Errorf("Expected no path without an interchange, got %v", err)
+	}
+}
+
+// TestInterchangeOutput checks that the simulation shows trains changing networks
+func TestInterchangeOutput(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, writeMap(t, interchangeMap), "waterloo", "paris.gare_de_lyon", "1")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	expected := []string{
+		"T1-london.victoria",
+		"T1-london.st_pancras",
+		"T1-paris.gare_du_nord(1/2)",
+		"T1-paris.gare_du_nord",
+		"T1-paris.chatelet",
+		"T1-paris.gare_de_lyon",
+	}
+	if strings.TrimSpace(string(output)) != strings.Join(expected, "\n") {
+		t.Errorf("Wanted:\n%s\ngot:\n%s", strings.Join(expected, "\n"), output)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/output"
+	"station/internal/pathfinding"
+	"testing"
+)
+
+// TestFindJobPaths checks that several groups of trains share a network without conflicts
+func TestFindJobPaths(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, capacityMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Capacity Map"]
+
+	jobs := []model.Job{
+		{Start: "beginning", End: "far", Trains: 3},
+		{Start: "far", End: "beginning", Trains: 2},
+		{Start: "near", End: "terminus", Trains: 2},
+	}
+
+	paths, err := pathfinding.FindJobPaths(jobs, stations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 7 {
+		t.Fatalf("Wanted 7 trains, got %d", len(paths))
+	}
+
+	// Trains are numbered job by job
+	trainID := 0
+	for _, job := range jobs {
+		for i := 0; i < job.Trains; i++ {
+			path := paths[trainID]
+			if path[0] != job.Start || path[len(path)-1] != job.End {
+				t.Errorf("Train T%d should run from %s to %s, got %v", trainID+1, job.Start, job.End, path)
+			}
+			trainID++
+		}
+	}
+
+	if conflicts := core.FindConflicts(paths, stations); len(conflicts) > 0 {
+		t.Errorf("Planned paths have conflicts: %v", conflicts)
+	}
+}
+
+// TestParseJob checks the start:end:trains job format
+func TestParseJob(t *testing.T) {
+	job, err := io.ParseJob("waterloo:st_pancras:3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job != (model.Job{Start: "waterloo", End: "st_pancras", Trains: 3}) {
+		t.Errorf("Wrong job: %+v", job)
+	}
+
+	for _, spec := range []string{"waterloo:st_pancras", "waterloo::3", "waterloo:st_pancras:0", "a:b:c:1"} {
+		if _, err := io.ParseJob(spec); err == nil {
+			t.Errorf("Expected an error for job '%s'", spec)
+		}
+	}
+}
+
+// TestJobFlags checks the -job flag end to end through the JSON report
+func TestJobFlags(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := filepath.Join(filepath.Dir(mainPath), "network.map")
+
+	cmd := exec.Command("go", "run", mainPath, "-format", "json", "-job", "waterloo:st_pancras:3", "-job", "euston:victoria:2", mapPath)
+	outputBytes, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result output.Result
+	if err := json.Unmarshal(outputBytes, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, outputBytes)
+	}
+
+	if len(result.Jobs) != 2 || len(result.Trains) != 5 {
+		t.Fatalf("Wanted 5 trains in 2 jobs, got %d trains in %d jobs", len(result.Trains), len(result.Jobs))
+	}
+	if got := result.Jobs[1].Trains; len(got) != 2 || got[0] != "T4" || got[1] != "T5" {
+		t.Errorf("Wanted trains T4 and T5 in the second job, got %v", got)
+	}
+	for _, train := range result.Trains[3:] {
+		last := train.Movements[len(train.Movements)-1]
+		if last.To != "victoria" {
+			t.Errorf("Train %s should end at victoria, got %s", train.ID, last.To)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/model"
+	"testing"
+)
+
+const brokenMap = `--- Broken Map ---
+stations:
+a,0,0
+b,1,x
+Bad,2,2
+c,3,3,0
+d,0,0
+e,5,5
+f,6,6
+g,7,7
+a,9,9
+h,8,8
+i,9,8
+
+connections:
+a-e
+a-b
+a-c 0
+a-e
+e-f capacity=2 speed=3
+f-g
+g-g
+e-zz
+h-i
+`
+
+// TestLintMap checks that every problem is reported with its line, column, severity and code
+func TestLintMap(t *testing.T) {
+	diagnostics, err := io.LintMap(writeMap(t, brokenMap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		line, column int
+		severity     string
+		code         string
+	}{
+		{4, 5, model.SeverityError, io.CodeInvalidCoordinate},
+		{5, 1, model.SeverityError, io.CodeInvalidName},
+		{6, 7, model.SeverityError, io.CodeInvalidPlatforms},
+		{7, 3, model.SeverityError, io.CodeSameCoordinates},
+		{9, 1, model.SeverityWarning, io.CodeUnreachable}, // e-f is rejected, so f and g are cut off
+		{11, 1, model.SeverityError, io.CodeDuplicateStation},
+		{12, 1, model.SeverityWarning, io.CodeUnreachable},
+		{18, 5, model.SeverityError, io.CodeInvalidTrack},
+		{19, 1, model.SeverityError, io.CodeDuplicateConnection},
+		{20, 16, model.SeverityError, io.CodeSyntax},
+		{22, 1, model.SeverityError, io.CodeSelfConnection},
+		{23, 3, model.SeverityError, io.CodeUnknownStation},
+	}
+
+	if len(diagnostics) != len(expected) {
+		for _, d := range diagnostics {
+			t.Log(d)
+		}
+		t.Fatalf("Wanted %d diagnostics, got %d", len(expected), len(diagnostics))
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Line != want.line || got.Column != want.column || got.Severity != want.severity || got.Code != want.code {
+			t.Errorf("Wanted %s %s at %d:%d, got %s", want.severity, want.code, want.line, want.column, got)
+		}
+	}
+}
+
+// TestLintIsolatedStation checks the warning for stations without connections
+func TestLintIsolatedStation(t *testing.T) {
+	diagnostics, err := io.LintMap(writeMap(t, weightedMap+"\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("Wanted no diagnostics for a valid map, got %v", diagnostics)
+	}
+
+	diagnostics, err = io.LintMap(writeMap(t, capacityMap+"lonely,9,9\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The station line follows the connections, so it is reported as a syntax error
+	if len(diagnostics) != 1 || diagnostics[0].Code != io.CodeSyntax {
+		t.Errorf("Wanted a syntax error for a station in the connections section, got %v", diagnostics)
+	}
+
+	isolated := "--- Isolated ---\nstations:\na,0,0\nb,1,1\nc,2,2\n\nconnections:\na-b\n"
+	diagnostics, err = io.LintMap(writeMap(t, isolated))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Code != io.CodeIsolatedStation || diagnostics[0].Line != 5 {
+		t.Errorf("Wanted an isolated station warning on line 5, got %v", diagnostics)
+	}
+}
+
+// TestLintExitCode checks that only errors make the lint command fail
+func TestLintExitCode(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "lint", filepath.Join(filepath.Dir(mainPath), "network.map"))
+	if outputBytes, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("Wanted a valid map to pass: %v\n%s", err, outputBytes)
+	}
+
+	cmd = exec.Command("go", "run", mainPath, "lint", writeMap(t, brokenMap))
+	if err := cmd.Run(); err == nil {
+		t.Errorf("Wanted a non-zero exit code for a map with errors")
+	}
+}
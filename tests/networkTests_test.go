@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// twinMap has two networks sharing the stations waterloo and st_pancras, the second one being faster
+const twinMap = `--- Slow Network ---
+stations:
+waterloo,0,0
+victoria,1,0
+st_pancras,2,0
+
+connections:
+waterloo-victoria
+victoria-st_pancras
+
+--- Fast Network ---
+stations:
+waterloo,0,0
+st_pancras,2,0
+
+connections:
+waterloo-st_pancras
+`
+
+// TestNetworkSelection checks that networks are chosen in file order unless one is named
+func TestNetworkSelection(t *testing.T) {
+	job := planner.Job{Start: "waterloo", End: "st_pancras", Trains: 1}
+
+	// The choice must not depend on the iteration order of Go maps
+	for i := 0; i < 20; i++ {
+		networks, err := planner.LoadMap(strings.NewReader(twinMap))
+		if err != nil {
+			t.Fatalf("Failed to load map: %v", err)
+		}
+		network, err := planner.SelectNetwork(networks, job)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if network.Name != "Slow Network" {
+			t.Fatalf("Wanted the first network in file order, got '%s'", network.Name)
+		}
+	}
+
+	networks, err := planner.LoadMap(strings.NewReader(twinMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	candidates := planner.Candidates(networks, job)
+	if len(candidates) != 2 || candidates[0].Name != "Slow Network" || candidates[1].Name != "Fast Network" {
+		t.Errorf("Wanted both networks as candidates in file order, got %v", candidates)
+	}
+
+	network, err := planner.SelectNamedNetwork(networks, "Fast Network", job)
+	if err != nil || network.Name != "Fast Network" {
+		t.Errorf("Wanted the named network, got %v, %v", network, err)
+	}
+	if _, err := planner.SelectNamedNetwork(networks, "Paris Network", job); !errors.Is(err, utils.ErrNetworkNotExist) {
+		t.Errorf("Expected an unknown network, got %v", err)
+	}
+	if _, err := planner.SelectNamedNetwork(networks, "Fast Network", planner.Job{Start: "victoria", End: "waterloo"}); !errors.Is(err, utils.ErrStartStationNotExist) {
+		t.Errorf("Expected victoria to be missing from the named network, got %v", err)
+	}
+}
+
+// TestNetworkFlag checks the ambiguity warning, the -network flag and the list-networks command
+func TestNetworkFlag(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := writeMap(t, twinMap)
+
+	run := func(args ...string) (string, string) {
+		cmd := exec.Command("go", append([]string{"run", mainPath}, args...)...)
+		cmd.Dir = filepath.Dir(mainPath)
+		var stdout, stderr strings.Builder
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Command failed: %v\n%s", err, stderr.String())
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	// Without -network the first network is used, with a warning naming both
+	stdout, stderr := run(mapPath, "waterloo", "st_pancras", "1")
+	if !strings.Contains(stderr, "'Slow Network', 'Fast Network'") || strings.TrimSpace(stdout) != "T1-victoria\nT1-st_pancras" {
+		t.Errorf("Unexpected output:\n%s\n%s", stdout, stderr)
+	}
+
+	stdout, stderr = run("-network", "Fast Network", mapPath, "waterloo", "st_pancras", "1")
+	if stderr != "" || strings.TrimSpace(stdout) != "T1-st_pancras" {
+		t.Errorf("Unexpected output:\n%s\n%s", stdout, stderr)
+	}
+
+	stdout, _ = run("list-networks", mapPath)
+	expected := "Slow Network: 3 station(s), 2 connection(s)\nFast Network: 2 station(s), 1 connection(s)\n"
+	if stdout != expected {
+		t.Errorf("Wanted:\n%s\ngot:\n%s", expected, stdout)
+	}
+}
+
+// TestSubcommandNetworkFlag checks that the subcommands take the -network flag and keep networks in file order
+func TestSubcommandNetworkFlag(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := writeMap(t, twinMap)
+	logPath := writeMap(t, "T1-st_pancras\n")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string // Expected parts of the output, in order
+		missing  string   // Text the output must not contain
+	}{
+		{"analyze", []string{"analyze", "-network", "Fast Network", mapPath, "waterloo", "st_pancras", "1"}, []string{"Network: Fast Network", "Minimum turns for 1 train(s): 1"}, ""},
+		{"suggest", []string{"suggest", "-network", "Fast Network", mapPath, "waterloo", "st_pancras", "1"}, []string{"Network: Fast Network", "Current turns for 1 train(s): 1"}, ""},
+		{"validate", []string{"validate", "-network", "Fast Network", mapPath, "waterloo", "st_pancras", logPath}, []string{"Schedule is valid: all trains reach st_pancras in 1 turns"}, ""},
+		{"validate first network", []string{"validate", mapPath, "waterloo", "st_pancras", logPath}, []string{"no connection between waterloo and st_pancras"}, ""},
+		{"resilience", []string{"resilience", "-network", "Fast Network", mapPath}, []string{"Network: Fast Network", "Bridges: st_pancras-waterloo"}, "Slow Network"},
+		{"resilience between stations", []string{"resilience", "-network", "Fast Network", mapPath, "waterloo", "st_pancras"}, []string{"Network: Fast Network", "Bridges between waterloo and st_pancras: st_pancras-waterloo"}, ""},
+		{"resilience in file order", []string{"resilience", mapPath}, []string{"Network: Slow Network", "Network: Fast Network"}, ""},
+		{"export dot in file order", []string{"export", "dot", mapPath}, []string{`graph "Slow Network"`, `graph "Fast Network"`}, ""},
+		{"export dot", []string{"export", "-network", "Fast Network", "dot", mapPath, "waterloo", "st_pancras", "1"}, []string{`graph "Fast Network"`, `label="T1"`}, "Slow Network"},
+		{"export geojson in file order", []string{"export", "geojson", mapPath}, []string{`"network": "Slow Network"`, `"network": "Fast Network"`}, ""},
+		{"export geojson", []string{"export", "-network", "Fast Network", "geojson", mapPath}, []string{`"name": "Fast Network"`}, "Slow Network"},
+		{"unknown network", []string{"analyze", "-network", "Paris Network", mapPath, "waterloo", "st_pancras", "1"}, []string{utils.ErrNetworkNotExist.Error()}, ""},
+		{"unknown network without stations", []string{"resilience", "-network", "Paris Network", mapPath}, []string{utils.ErrNetworkNotExist.Error()}, "Network:"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command("go", append([]string{"run", mainPath}, tc.args...)...)
+			cmd.Dir = filepath.Dir(mainPath)
+			outputBytes, _ := cmd.CombinedOutput()
+			output := string(outputBytes)
+
+			rest := output
+			for _, part := range tc.expected {
+				i := strings.Index(rest, part)
+				if i == -1 {
+					t.Fatalf("Wanted %q in order in the output:\n%s", part, output)
+				}
+				rest = rest[i+len(part):]
+			}
+			if tc.missing != "" && strings.Contains(output, tc.missing) {
+				t.Errorf("Wanted no %q in the output:\n%s", tc.missing, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"station/internal/output"
+	"strings"
+	"testing"
+)
+
+// TestJSONOutput checks the machine-readable report of a simulation
+func TestJSONOutput(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := filepath.Join(filepath.Dir(mainPath), "network.map")
+
+	cmd := exec.Command("go", "run", mainPath, "-format", "json", mapPath, "waterloo", "st_pancras", "4")
+	outputBytes, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result output.Result
+	if err := json.Unmarshal(outputBytes, &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, outputBytes)
+	}
+
+	if result.Network != "London Network Map" {
+		t.Errorf("Wanted network 'London Network Map', got '%s'", result.Network)
+	}
+	if result.Turns != 3 {
+		t.Errorf("Wanted 3 turns, got %d", result.Turns)
+	}
+	if len(result.Trains) != 4 || len(result.Paths) != 2 {
+		t.Errorf("Wanted 4 trains on 2 paths, got %d trains on %d paths", len(result.Trains), len(result.Paths))
+	}
+	for _, train := range result.Trains {
+		last := train.Movements[len(train.Movements)-1]
+		if last.To != "st_pancras" || last.Arrive != train.Arrival {
+			t.Errorf("Train %s does not end at st_pancras in turn %d: %v", train.ID, train.Arrival, train.Movements)
+		}
+	}
+}
+
+// TestCSVOutput checks that every movement is written as a row
+func TestCSVOutput(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	mapPath := filepath.Join(filepath.Dir(mainPath), "network.map")
+
+	cmd := exec.Command("go", "run", mainPath, "-format", "csv", mapPath, "waterloo", "st_pancras", "4")
+	outputBytes, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A header plus two movements for each of the four trains
+	lines := strings.Split(strings.TrimSpace(string(outputBytes)), "\n")
+	if len(lines) != 9 || lines[0] != "network,train,from,to,depart,arrive" {
+		t.Errorf("Unexpected CSV output:\n%s", outputBytes)
+	}
+}
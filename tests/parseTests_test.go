@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/utils"
+	"strings"
+	"testing"
+)
+
+// TestParse checks parsing maps held in memory, in both supported formats
+func TestParse(t *testing.T) {
+	networks, err := io.Parse(strings.NewReader(weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	if stations := networks["Weighted Map"]; len(stations) != 4 || stations["waterloo"].TrackTo("victoria").Duration != 3 {
+		t.Errorf("Wrong network parsed: %v", networks)
+	}
+
+	networks, err = io.Parse(strings.NewReader(`  {"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"name": "alpha"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse GeoJSON: %v", err)
+	}
+	if len(networks["map"]) != 1 {
+		t.Errorf("Wanted the station in the default network, got %v", networks)
+	}
+}
+
+// TestParseConnectionErrors checks that a broken connection is reported as such,
+// with the missing station available to callers that know the start and end stations
+func TestParseConnectionErrors(t *testing.T) {
+	_, err := io.Parse(strings.NewReader(strings.Replace(weightedMap, "waterloo,3,1\n", "", 1)))
+
+	var missing *utils.StationError
+	if !errors.Is(err, utils.ErrStationDoesNotExistInConnections) || !errors.As(err, &missing) || missing.Station != "waterloo" {
+		t.Fatalf("Expected a connection to the missing station waterloo, got %v", err)
+	}
+}
+
+// TestReadMapStdin checks that "-" reads the map from standard input
+func TestReadMapStdin(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "-", "waterloo", "st_pancras", "4")
+	cmd.Dir = filepath.Dir(mainPath)
+	cmd.Stdin = strings.NewReader(weightedMap)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run with a map on stdin: %v\n%s", err, output)
+	}
+
+	if lines := strings.Count(strings.TrimSpace(string(output)), "\n") + 1; lines != 5 {
+		t.Errorf("Wanted 5 turns, got %d:\n%s", lines, output)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"station/internal/utils"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// TestPlannerPlan checks loading a map from a reader and planning a schedule with the public API
+func TestPlannerPlan(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+
+	network, err := planner.SelectNetwork(networks, planner.Job{Start: "waterloo", End: "st_pancras", Trains: 4})
+	if err != nil {
+		t.Fatalf("Failed to select network: %v", err)
+	}
+	if network.Name != "Weighted Map" || !network.HasStation("euston") || len(network.Stations()) != 4 {
+		t.Errorf("Wrong network selected: %s %v", network.Name, network.Stations())
+	}
+
+	schedule, err := planner.Plan(network, "waterloo", "st_pancras", 4, planner.Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.Turns != 5 || len(schedule.Paths) != 4 || len(schedule.Trains) != 4 {
+		t.Errorf("Wanted 4 trains arriving by turn 5, got %d trains in %d turns", len(schedule.Paths), schedule.Turns)
+	}
+
+	// The JSON report matches the schedule
+	var buf bytes.Buffer
+	if err := schedule.WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var result struct {
+		Network string `json:"network"`
+		Turns   int    `json:"turns"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if result.Network != "Weighted Map" || result.Turns != schedule.Turns {
+		t.Errorf("JSON does not match the schedule: %s", buf.String())
+	}
+
+	// The text output has one line per turn
+	buf.Reset()
+	if err := schedule.WriteText(&buf); err != nil {
+		t.Fatalf("Failed to write text: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != schedule.Turns {
+		t.Errorf("Wanted %d lines, got %d:\n%s", schedule.Turns, lines, buf.String())
+	}
+}
+
+// TestPlannerOptions checks that extra jobs are planned together with the first one
+func TestPlannerOptions(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(capacityMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+
+	options := planner.Options{Jobs: []planner.Job{{Start: "far", End: "beginning", Trains: 2}}}
+	schedule, err := planner.Plan(networks["Capacity Map"], "beginning", "far", 3, options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(schedule.Jobs) != 2 || len(schedule.Paths) != 5 {
+		t.Errorf("Wanted 5 trains in 2 jobs, got %d trains in %d jobs", len(schedule.Paths), len(schedule.Jobs))
+	}
+	if path := schedule.Paths[3]; path[0] != "far" || path[len(path)-1] != "beginning" {
+		t.Errorf("Train 4 should belong to the second job, got %v", path)
+	}
+}
+
+// TestPlannerErrors checks that the public API reports typed errors
+func TestPlannerErrors(t *testing.T) {
+	networks, err := planner.LoadMap(strings.NewReader(weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	network := networks["Weighted Map"]
+
+	if _, err := planner.Plan(network, "waterloo", "nowhere", 2, planner.Options{}); !errors.Is(err, utils.ErrEndStationNotExist) {
+		t.Errorf("Expected a missing end station, got %v", err)
+	}
+	if _, err := planner.Plan(network, "waterloo", "euston", 0, planner.Options{}); !errors.Is(err, utils.ErrInvalidTrainCount) {
+		t.Errorf("Expected an invalid train count, got %v", err)
+	}
+	if _, err := planner.LoadMap(strings.NewReader("stations:\n")); !errors.Is(err, utils.ErrDataOutsideNetwork) {
+		t.Errorf("Expected data outside of a network, got %v", err)
+	}
+}
+
+// TestPlannerLoadGeoJSON checks that LoadMap recognises GeoJSON maps
+func TestPlannerLoadGeoJSON(t *testing.T) {
+	geoJSON := `
+	{"type": "FeatureCollection", "name": "GIS Map", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}, "properties": {"name": "alpha"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2, 1]}, "properties": {"name": "beta"}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [2, 1]]}, "properties": {}}
+	]}`
+
+	networks, err := planner.LoadMap(strings.NewReader(geoJSON))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	if network := networks["GIS Map"]; network == nil || !network.HasStation("alpha") || !network.HasStation("beta") {
+		t.Errorf("Wrong networks loaded: %v", networks)
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os/exec"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/model"
+	"station/internal/utils"
+	"station/internal/visualization"
+	"strings"
+	"testing"
+)
+
+// TestSinglePoints checks the articulation points and bridges of a network and of a trip through it
+func TestSinglePoints(t *testing.T) {
+	networks, err := io.Parse(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	stations := networks["Line"]
+
+	resilience := core.FindSinglePoints(stations)
+	if fmt.Sprint(resilience.ArticulationPoints) != "[b c]" || fmt.Sprint(resilience.Bridges) != "[[a b] [c d]]" {
+		t.Errorf("Wrong single points of failure: %+v", resilience)
+	}
+
+	testCases := []struct {
+		start, end string
+		points     string
+		bridges    string
+	}{
+		{"a", "d", "[b c]", "[[a b] [c d]]"},
+		{"a", "e", "[b]", "[[a b]]"},
+		{"b", "c", "[]", "[]"},
+		{"e", "d", "[c]", "[[c d]]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.start+"-"+tc.end, func(t *testing.T) {
+			separating, err := core.FindSeparatingPoints(stations, tc.start, tc.end)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprint(separating.ArticulationPoints) != tc.points || fmt.Sprint(separating.Bridges) != tc.bridges {
+				t.Errorf("Wanted %s and %s, got %+v", tc.points, tc.bridges, separating)
+			}
+		})
+	}
+
+	if _, err := core.FindSeparatingPoints(stations, "a", "nowhere"); !errors.Is(err, utils.ErrEndStationNotExist) {
+		t.Errorf("Expected a missing end station, got %v", err)
+	}
+	var stationErr *utils.StationError
+	if _, err := core.FindSeparatingPoints(stations, "a", "a"); !errors.As(err, &stationErr) || stationErr.Kind != utils.ErrSameStartEndStation || stationErr.Station != "a" {
+		t.Errorf("Expected the same start and end station a, got %v", err)
+	}
+
+	// Without the track c-d, d cannot be reached from a
+	island, err := io.Parse(strings.NewReader(strings.Replace(lineMap, "c-d\n", "", 1)))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	var connectionErr *utils.ConnectionError
+	if _, err := core.FindSeparatingPoints(island["Line"], "a", "d"); !errors.As(err, &connectionErr) || connectionErr.Kind != utils.ErrNoPath || connectionErr.From != "a" || connectionErr.To != "d" {
+		t.Errorf("Expected no path from a to d, got %v", err)
+	}
+
+	// The line fits the smallest 1000x800 image, where the grid unit is 299 pixels and a lies at pixel
+	// 50,750, so the bridge a-b passes through 200,750 and the ring around b through 349,743, on the
+	// track to e above b
+	darkRed := color.RGBA{139, 0, 0, 255} // The highlight color of the PNG images
+	plain, highlighted := drawPNG(t, stations), drawPNG(t, stations, resilience)
+	for _, pixel := range []image.Point{{200, 750}, {349, 743}} {
+		if c := highlighted.At(pixel.X, pixel.Y); c != color.Color(darkRed) {
+			t.Errorf("Expected the highlight color at %v, got %v", pixel, c)
+		}
+		if c := plain.At(pixel.X, pixel.Y); c == color.Color(darkRed) {
+			t.Errorf("Expected no highlight at %v without single points of failure", pixel)
+		}
+	}
+}
+
+// drawPNG draws a network to a PNG image and decodes it again
+func drawPNG(t *testing.T, stations map[string]*model.Station, weak ...model.Resilience) image.Image {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := visualization.WritePNG(&buf, stations, nil, weak...); err != nil {
+		t.Fatalf("Failed to write PNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Invalid PNG: %v", err)
+	}
+	return img
+}
+
+// TestResilienceCommand checks the report of the resilience subcommand
+func TestResilienceCommand(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "resilience", writeMap(t, lineMap), "a", "e")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	expected := `Network: Line
+Articulation points: b, c
+Bridges: a-b, c-d
+Articulation points between a and e: b
+Bridges between a and e: a-b
+`
+	if string(output) != expected {
+		t.Errorf("Wanted:\n%s\ngot:\n%s", expected, output)
+	}
+}
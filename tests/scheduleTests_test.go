@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"testing"
+)
+
+// TestScheduleLowerBound checks that the planned schedules reach the proven minimum number of turns
+func TestScheduleLowerBound(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	// Get the directory containing main.go
+	projectRoot := filepath.Dir(mainPath)
+
+	for _, tc := range validTestCases {
+		t.Run(fmt.Sprintf("%s to %s", tc.startStation, tc.endStation), func(t *testing.T) {
+			mapPath := filepath.Join(projectRoot, tc.mapFile)
+
+			networks, err := io.ReadMapFile(mapPath)
+			if err != nil {
+				t.Fatalf("Failed to read map: %v", err)
+			}
+
+			_, stations, err := core.FindAppropriateMap(networks, tc.startStation, tc.endStation)
+			if err != nil {
+				t.Fatalf("Failed to find network: %v", err)
+			}
+
+			schedule, err := pathfinding.PlanSchedule(tc.startStation, tc.endStation, stations, tc.numberOfTrains)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if schedule.LowerBound != tc.expectedTurns {
+				t.Errorf("%sWanted lower bound of %d turns, got %d%s", utils.Red, tc.expectedTurns, schedule.LowerBound, utils.Reset)
+			}
+			if schedule.Turns != schedule.LowerBound {
+				t.Errorf("%sSchedule needs %d turns, but the lower bound is %d%s", utils.Red, schedule.Turns, schedule.LowerBound, utils.Reset)
+			}
+
+			total := 0
+			for _, n := range schedule.Trains {
+				total += n
+			}
+			if total != tc.numberOfTrains {
+				t.Errorf("%sSchedule moves %d trains, wanted %d%s", utils.Red, total, tc.numberOfTrains, utils.Reset)
+			}
+		})
+	}
+}
+
+// TestScheduleTrains checks the distribution of trains over fixed path sets
+func TestScheduleTrains(t *testing.T) {
+	testCases := []struct {
+		lengths   []int
+		numTrains int
+		trains    []int
+		turns     int
+	}{
+		{[]int{1, 3}, 20, []int{11, 9}, 11},
+		{[]int{2, 3}, 9, []int{5, 4}, 6},
+		{[]int{2, 2}, 4, []int{2, 2}, 3},
+		{[]int{1, 5}, 3, []int{3, 0}, 3},
+		{[]int{3, 3, 3}, 4, []int{2, 1, 1}, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.lengths, tc.numTrains), func(t *testing.T) {
+			// Build dummy paths with the requested number of single-turn moves
+			paths := make([][]string, len(tc.lengths))
+			for i, l := range tc.lengths {
+				for j := 0; j <= l; j++ {
+					paths[i] = append(paths[i], fmt.Sprintf("s%d_%d", i, j))
+				}
+			}
+
+			schedule := pathfinding.ScheduleTrains(paths, tc.lengths, tc.numTrains)
+			if schedule.Turns != tc.turns || schedule.LowerBound != tc.turns {
+				t.Errorf("Wanted %d turns, got %d (lower bound %d)", tc.turns, schedule.Turns, schedule.LowerBound)
+			}
+			if fmt.Sprint(schedule.Trains) != fmt.Sprint(tc.trains) {
+				t.Errorf("Wanted trains %v, got %v", tc.trains, schedule.Trains)
+			}
+		})
+	}
+}
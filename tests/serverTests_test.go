@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"station/internal/server"
+	"station/planner"
+	"strings"
+	"testing"
+)
+
+// newTestServer serves the weighted map under the name "weighted"
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	networks, err := planner.LoadMap(strings.NewReader(weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to load map: %v", err)
+	}
+	srv := httptest.NewServer(server.New(map[string]map[string]*planner.Network{"weighted": networks}).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestServerPlan checks planning on a loaded map and on a map sent with the request
+func TestServerPlan(t *testing.T) {
+	srv := newTestServer(t)
+
+	testCases := []struct {
+		name   string
+		body   string
+		status int
+		turns  int
+	}{
+		{"loaded map", `{"map": "weighted", "start": "waterloo", "end": "st_pancras", "trains": 4}`, http.StatusOK, 5},
+		{"map data", `{"mapData": ` + jsonString(capacityMap) + `, "start": "beginning", "end": "terminus", "trains": 2}`, http.StatusOK, 1},
+		{"unknown map", `{"map": "paris", "start": "waterloo", "end": "st_pancras", "trains": 4}`, http.StatusNotFound, 0},
+		{"unknown station", `{"map": "weighted", "start": "waterloo", "end": "nowhere", "trains": 4}`, http.StatusNotFound, 0},
+		{"invalid train count", `{"map": "weighted", "start": "waterloo", "end": "st_pancras", "trains": 0}`, http.StatusBadRequest, 0},
+		{"invalid body", `{"map": `, http.StatusBadRequest, 0},
+		{"too many trains", `{"map": "weighted", "start": "waterloo", "end": "st_pancras", "trains": 6000, "return": 6000}`, http.StatusBadRequest, 0},
+		{"too many trains overflowing", `{"map": "weighted", "start": "waterloo", "end": "st_pancras", "trains": 9000000000000000000, "return": 9000000000000000000}`, http.StatusBadRequest, 0},
+		{"long closure", `{"map": "weighted", "start": "waterloo", "end": "st_pancras", "trains": 4, "closures": ["euston:1-100000000"]}`, http.StatusBadRequest, 0},
+		{"oversized body", `{"mapData": "` + strings.Repeat(" ", 16<<20) + `"}`, http.StatusRequestEntityTooLarge, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/plan", "application/json", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var result struct {
+				Turns int    `json:"turns"`
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Invalid JSON response: %v", err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Fatalf("Wanted status %d, got %d (%s)", tc.status, resp.StatusCode, result.Error)
+			}
+			if tc.status == http.StatusOK && result.Turns != tc.turns {
+				t.Errorf("Wanted %d turns, got %d", tc.turns, result.Turns)
+			}
+			if tc.status != http.StatusOK && result.Error == "" {
+				t.Errorf("Expected an error message")
+			}
+		})
+	}
+}
+
+// TestServerValidateMap checks that map problems are reported as diagnostics
+func TestServerValidateMap(t *testing.T) {
+	srv := newTestServer(t)
+
+	testCases := []struct {
+		name        string
+		body        string
+		valid       bool
+		diagnostics int
+	}{
+		{"valid map", weightedMap, true, 0},
+		{"broken map", brokenMap, false, 12},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/validate-map", "text/plain", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var result struct {
+				Valid    bool `json:"valid"`
+				Networks []struct {
+					Name     string `json:"name"`
+					Stations int    `json:"stations"`
+				} `json:"networks"`
+				Diagnostics []struct {
+					Code string `json:"code"`
+				} `json:"diagnostics"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Invalid JSON response: %v", err)
+			}
+			if result.Valid != tc.valid || len(result.Diagnostics) != tc.diagnostics {
+				t.Errorf("Wanted valid=%v with %d diagnostics, got valid=%v with %d", tc.valid, tc.diagnostics, result.Valid, len(result.Diagnostics))
+			}
+			if tc.valid && (len(result.Networks) != 1 || result.Networks[0].Stations != 4) {
+				t.Errorf("Wrong networks reported: %v", result.Networks)
+			}
+		})
+	}
+	// A map cut off at the size limit is rejected rather than linted in part
+	resp, err := http.Post(srv.URL+"/validate-map", "text/plain", strings.NewReader(weightedMap+strings.Repeat("#", 16<<20)))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Wanted status %d for an oversized map, got %d", http.StatusRequestEntityTooLarge, resp.StatusCode)
+	}
+}
+
+// TestServerVisualization checks that networks are drawn as PNG images
+func TestServerVisualization(t *testing.T) {
+	srv := newTestServer(t)
+
+	testCases := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"network", "/networks/Weighted%20Map/visualization.png", http.StatusOK},
+		{"network with routes", "/networks/Weighted%20Map/visualization.png?start=waterloo&end=st_pancras&trains=2", http.StatusOK},
+		{"unknown network", "/networks/Paris/visualization.png", http.StatusNotFound},
+		{"invalid train count", "/networks/Weighted%20Map/visualization.png?start=waterloo&end=st_pancras&trains=x", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tc.path)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("Wanted status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			var buf bytes.Buffer
+			buf.ReadFrom(resp.Body)
+			if _, err := png.Decode(&buf); err != nil {
+				t.Errorf("Response is not a PNG image: %v", err)
+			}
+		})
+	}
+}
+
+// jsonString quotes a string as a JSON string literal
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// TestServerTimeouts checks that the HTTP server does not wait for clients indefinitely
+func TestServerTimeouts(t *testing.T) {
+	srv := server.New(nil).HTTPServer(":8080")
+	if srv.Addr != ":8080" || srv.ReadHeaderTimeout == 0 || srv.ReadTimeout == 0 || srv.WriteTimeout == 0 || srv.IdleTimeout == 0 {
+		t.Errorf("Expected an address and all timeouts to be set, got %+v", srv)
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"station/internal/utils"
+	"strings"
+	"testing"
+)
+
+// TestSimulationEvents checks the event stream of a train waiting, travelling and finishing
+func TestSimulationEvents(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+
+	sim, err := pathfinding.NewSimulation([][]string{{"waterloo", "waterloo", "victoria", "st_pancras"}}, stations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	sim.Run(func(turn int, events []pathfinding.Event) {
+		for _, event := range events {
+			got = append(got, fmt.Sprintf("%d:%s:%s", turn, event.Kind, event.To))
+		}
+	})
+
+	expected := []string{
+		"1:wait:waterloo",
+		"2:depart:victoria",
+		"3:transit:victoria",
+		"4:arrive:victoria",
+		"5:depart:st_pancras",
+		"5:arrive:st_pancras",
+		"5:finish:st_pancras",
+	}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Unexpected events:\nwant %v\ngot  %v", expected, got)
+	}
+	if sim.Turns() != 5 {
+		t.Errorf("Wanted 5 turns, got %d", sim.Turns())
+	}
+}
+
+// TestPrintText checks that the text printer consumes the event stream like SimTrain used to print
+func TestPrintText(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+
+	sim, err := pathfinding.NewSimulation([][]string{{"waterloo", "victoria", "st_pancras"}, {"waterloo", "euston", "st_pancras"}}, stations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := pathfinding.PrintText(&out, sim); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "T1-victoria(1/3) T2-euston\nT1-victoria(2/3) T2-st_pancras(1/2)\nT1-victoria T2-st_pancras\nT1-st_pancras\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\nwant %q\ngot  %q", expected, out.String())
+	}
+}
+
+// TestSimulationRejectsConflicts checks that conflicting schedules are not simulated
+func TestSimulationRejectsConflicts(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+
+	_, err = pathfinding.NewSimulation([][]string{{"waterloo", "euston"}, {"euston", "waterloo"}}, stations)
+	var conflict *utils.ConflictError
+	if !errors.As(err, &conflict) || !strings.Contains(conflict.Message, "head-on") {
+		t.Errorf("Expected a head-on conflict, got %v", err)
+	}
+}
@@ -9,6 +9,23 @@ import (
 	"testing"
 )
 
+// validTestCases lists the expected minimum number of turns for each network in network.map
+var validTestCases = []struct {
+	mapFile        string
+	startStation   string
+	endStation     string
+	numberOfTrains int
+	expectedTurns  int
+}{
+	{"network.map", "waterloo", "st_pancras", 4, 3},
+	{"network.map", "beginning", "terminus", 20, 11},
+	{"network.map", "beethoven", "part", 9, 6},
+	{"network.map", "small", "large", 9, 8},
+	{"network.map", "two", "four", 4, 6},
+	{"network.map", "jungle", "desert", 10, 8},
+	{"network.map", "bond_square", "space_port", 4, 6},
+}
+
 func TestValidCases(t *testing.T) {
 	mainPath, err := findMainGo()
 	if err != nil {
@@ -18,22 +35,6 @@ func TestValidCases(t *testing.T) {
 	// Get the directory containing main.go
 	projectRoot := filepath.Dir(mainPath)
 
-	validTestCases := []struct {
-		mapFile        string
-		startStation   string
-		endStation     string
-		numberOfTrains int
-		expectedTurns  int
-	}{
-		{"network.map", "waterloo", "st_pancras", 4, 3},
-		{"network.map", "beginning", "terminus", 20, 11},
-		{"network.map", "beethoven", "part", 9, 6},
-		{"network.map", "small", "large", 9, 8},
-		{"network.map", "two", "four", 4, 6},
-		{"network.map", "jungle", "desert", 10, 8},
-		{"network.map", "bond_square", "space_port", 4, 6},
-	}
-
 	for _, tc := range validTestCases {
 		t.Run(fmt.Sprintf("%s to %s", tc.startStation, tc.endStation), func(t *testing.T) {
 			mapPath := filepath.Join(projectRoot, tc.mapFile)
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSuggestConnections checks that candidate connections are ranked by the turns they save
+func TestSuggestConnections(t *testing.T) {
+	networks, err := io.Parse(strings.NewReader(lineMap))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	stations := networks["Line"]
+
+	candidates := pathfinding.CandidateConnections(stations, 2)
+	if fmt.Sprint(candidates) != "[[a e] [a c] [b d]]" {
+		t.Errorf("Wrong candidates: %v", candidates)
+	}
+
+	suggestions, baseline, err := pathfinding.SuggestConnections("a", "d", stations, 1, pathfinding.CandidateConnections(stations, 3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if baseline != 3 {
+		t.Errorf("Wanted 3 turns without a new connection, got %d", baseline)
+	}
+	var ranking []string
+	for _, suggestion := range suggestions {
+		ranking = append(ranking, fmt.Sprintf("%s-%s:%d", suggestion.From, suggestion.To, suggestion.Reduction))
+	}
+	if strings.Join(ranking, " ") != "a-d:2 a-c:1 b-d:1" {
+		t.Errorf("Wrong ranking: %v", ranking)
+	}
+
+	// The network itself is left untouched
+	if len(stations["a"].Connections) != 1 || stations["a"].Tracks["d"] != nil {
+		t.Errorf("The network was modified: %v", stations["a"].Connections)
+	}
+}
+
+// TestSuggestLargeGrid checks that thousands of candidates on a large map are evaluated quickly.
+// On a grid no diagonal saves a turn for trains from one corner to the other.
+func TestSuggestLargeGrid(t *testing.T) {
+	const size = 70
+	var grid strings.Builder
+	grid.WriteString("--- Grid ---\nstations:\n")
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			fmt.Fprintf(&grid, "s%d_%d,%d,%d\n", x, y, x, y)
+		}
+	}
+	grid.WriteString("connections:\n")
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if x+1 < size {
+				fmt.Fprintf(&grid, "s%d_%d-s%d_%d\n", x, y, x+1, y)
+			}
+			if y+1 < size {
+				fmt.Fprintf(&grid, "s%d_%d-s%d_%d\n", x, y, x, y+1)
+			}
+		}
+	}
+	networks, err := io.Parse(strings.NewReader(grid.String()))
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	stations := networks["Grid"]
+
+	began := time.Now()
+	candidates := pathfinding.CandidateConnections(stations, 1.5)
+	suggestions, baseline, err := pathfinding.SuggestConnections("s0_0", "s69_69", stations, 10, candidates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(candidates) != 9522 || baseline != 142 || len(suggestions) != 0 {
+		t.Errorf("Wanted no suggestion among 9522 candidates and 142 turns, got %v among %d and %d turns", suggestions, len(candidates), baseline)
+	}
+	if elapsed := time.Since(began); elapsed > 10*time.Second {
+		t.Errorf("Suggesting connections on a %dx%d grid took %v", size, size, elapsed)
+	}
+}
+
+// TestSuggestCommand checks the report of the suggest subcommand
+func TestSuggestCommand(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", mainPath, "suggest", "-distance", "3", "-top", "2", writeMap(t, lineMap), "a", "d", "1")
+	cmd.Dir = filepath.Dir(mainPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	expected := `Network: Line
+Current turns for 1 train(s): 3
+Candidates within distance 3: 5
+1. a-d (distance 3.0): 1 turns (-2)
+2. a-c (distance 2.0): 2 turns (-1)
+`
+	if string(output) != expected {
+		t.Errorf("Wanted:\n%s\ngot:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	stationio "station/internal/io"
+	"station/internal/model"
+	"station/internal/visualization"
+	"strings"
+	"testing"
+)
+
+// TestWriteSVG checks that the SVG is well-formed and carries real labels and a legend
+func TestWriteSVG(t *testing.T) {
+	networks, err := stationio.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+	paths := [][]string{
+		{"waterloo", "victoria", "st_pancras"},
+		{"waterloo", "euston", "st_pancras"},
+		{"waterloo", "waterloo", "victoria", "st_pancras"},
+	}
+
+	var buf bytes.Buffer
+	if err := visualization.WriteSVG(&buf, stations, paths); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Walk the whole document to make sure it parses
+	var texts []string
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed: %v", err)
+		}
+		if data, ok := token.(xml.CharData); ok && strings.TrimSpace(string(data)) != "" {
+			texts = append(texts, string(data))
+		}
+	}
+
+	all := strings.Join(texts, "\n")
+	for _, want := range []string{"st_pancras", "T1, T3: waterloo - victoria - st_pancras", "T2: waterloo - euston - st_pancras"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("SVG does not contain '%s'", want)
+		}
+	}
+}
+
+// TestSVGGrowsWithMap checks that large maps get a larger canvas instead of overlapping stations
+func TestSVGGrowsWithMap(t *testing.T) {
+	stations := map[string]*model.Station{
+		"a": {Name: "a", X: 0, Y: 0},
+		"b": {Name: "b", X: 100, Y: 1},
+	}
+	stations["a"].Connections = []*model.Station{stations["b"]}
+	stations["b"].Connections = []*model.Station{stations["a"]}
+
+	var buf bytes.Buffer
+	if err := visualization.WriteSVG(&buf, stations, [][]string{{"a", "b"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var svg struct {
+		Width int `xml:"width,attr"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatalf("SVG is not well-formed: %v", err)
+	}
+	if svg.Width < 4000 {
+		t.Errorf("Wanted a canvas of at least 4000 pixels for 100 units, got %d", svg.Width)
+	}
+}
+
+// TestPNGGrowsWithMap checks that the PNG canvas grows with the map like the SVG one, up to its limit
+func TestPNGGrowsWithMap(t *testing.T) {
+	testCases := []struct {
+		name          string
+		maxX, maxY    int
+		width, height int
+	}{
+		{"small map", 3, 1, 1000, 800},
+		{"wide map", 50, 1, 2100, 800},
+		{"tall map", 1, 30, 1000, 1300},
+		{"huge map", 1000, 1000, 4000, 4000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stations := map[string]*model.Station{
+				"a": {Name: "a", X: 0, Y: 0},
+				"b": {Name: "b", X: tc.maxX, Y: tc.maxY},
+			}
+			stations["a"].Connections = []*model.Station{stations["b"]}
+			stations["b"].Connections = []*model.Station{stations["a"]}
+
+			size := drawPNG(t, stations).Bounds().Size()
+			if size.X != tc.width || size.Y != tc.height {
+				t.Errorf("Wanted a %dx%d canvas, got %v", tc.width, tc.height, size)
+			}
+		})
+	}
+}
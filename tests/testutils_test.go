@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"os"
 	"path/filepath"
+	"testing"
 )
 
 // findMainGo searches for main.go in the parent directory
@@ -13,3 +15,13 @@ func findMainGo() (string, error) {
 	mainPath := filepath.Join(dir, "main.go")
 	return mainPath, nil
 }
+
+// writeMap stores the given map contents in a temporary file and returns its path
+func writeMap(t *testing.T, contents string) string {
+	t.Helper()
+	mapPath := filepath.Join(t.TempDir(), "test.map")
+	if err := os.WriteFile(mapPath, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write map: %v", err)
+	}
+	return mapPath
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"fmt"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/pathfinding"
+	"strings"
+	"testing"
+)
+
+const capacityMap = `--- Capacity Map ---
+stations:
+beginning,0,0
+near,1,0
+far,1,3
+terminus,0,3
+
+connections:
+beginning-near
+beginning-terminus capacity=2
+near-far
+terminus-far
+`
+
+// TestTrackCapacity checks that double track lets two trains leave together
+func TestTrackCapacity(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, capacityMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Capacity Map"]
+
+	if capacity := stations["beginning"].TrackTo("terminus").Capacity; capacity != 2 {
+		t.Errorf("Wanted capacity 2 for beginning-terminus, got %d", capacity)
+	}
+	if capacity := stations["near"].TrackTo("far").Capacity; capacity != 1 {
+		t.Errorf("Wanted default capacity 1 for near-far, got %d", capacity)
+	}
+
+	schedule, err := pathfinding.PlanSchedule("beginning", "terminus", stations, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Two trains per turn on the direct track and one on the detour: 2T + (T-2) >= 20
+	if schedule.Turns != 8 {
+		t.Errorf("Wanted 8 turns, got %d", schedule.Turns)
+	}
+
+	paths, _, err := pathfinding.FindPaths("beginning", "terminus", stations, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conflicts := core.FindConflicts(paths, stations); len(conflicts) > 0 {
+		t.Errorf("Planned paths have conflicts: %v", conflicts)
+	}
+}
+
+// TestInvalidTrackCapacity checks that malformed capacities are rejected
+func TestInvalidTrackCapacity(t *testing.T) {
+	mapPath := writeMap(t, strings.Replace(capacityMap, "capacity=2", "capacity=0", 1))
+	if _, err := io.ReadMap(mapPath); err == nil {
+		t.Errorf("Expected an error for capacity=0")
+	}
+}
+
+// TestFindConflicts checks the track and station rules on hand-made schedules
+func TestFindConflicts(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, capacityMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Capacity Map"]
+
+	testCases := []struct {
+		name     string
+		paths    [][]string
+		expected string
+	}{
+		{"head-on swap", [][]string{{"near", "far"}, {"far", "near"}}, "head-on conflict"},
+		{"single track overload", [][]string{{"beginning", "near"}, {"beginning", "near"}}, "capacity is 1"},
+		{"double track", [][]string{{"beginning", "terminus"}, {"beginning", "terminus"}}, ""},
+		{"occupied station", [][]string{{"beginning", "near", "near", "far"}, {"terminus", "far", "near", "beginning"}}, "already occupied"},
+		{"missing connection", [][]string{{"beginning", "far"}}, "no connection"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conflicts := core.FindConflicts(tc.paths, stations)
+			if tc.expected == "" {
+				if len(conflicts) > 0 {
+					t.Errorf("Expected no conflicts, got %v", conflicts)
+				}
+				return
+			}
+			if len(conflicts) == 0 || !strings.Contains(conflicts[0].Message, tc.expected) {
+				t.Errorf("Expected conflict containing '%s', got %v", tc.expected, conflicts)
+			}
+		})
+	}
+}
+
+const weightedMap = `--- Weighted Map ---
+stations:
+waterloo,3,1
+victoria,6,7
+euston,11,23
+st_pancras,5,15
+
+connections:
+waterloo-victoria 3
+waterloo-euston
+st_pancras-euston duration=2
+victoria-st_pancras
+`
+
+// TestTrackDuration checks that travel times are honoured by the router and the movements
+func TestTrackDuration(t *testing.T) {
+	networks, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	stations := networks["Weighted Map"]
+
+	if duration := stations["victoria"].TrackTo("waterloo").Duration; duration != 3 {
+		t.Errorf("Wanted duration 3 for waterloo-victoria, got %d", duration)
+	}
+
+	// The euston route takes 3 turns and the victoria route 4, so 3+1 trains arrive by turn 5
+	schedule, err := pathfinding.PlanSchedule("waterloo", "st_pancras", stations, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schedule.Turns != 5 || schedule.LowerBound != 5 {
+		t.Errorf("Wanted 5 turns, got %d (lower bound %d)", schedule.Turns, schedule.LowerBound)
+	}
+
+	movements := core.CreateMovements([]string{"waterloo", "waterloo", "victoria", "st_pancras"}, 0, stations)
+	if movements[1].Depart != 2 || movements[1].Arrive != 4 || movements[2].Arrive != 5 {
+		t.Errorf("Unexpected movement times: %v", movements)
+	}
+}
+
+const platformMap = `--- Platform Map ---
+stations:
+start,0,0
+north,1,1
+south,1,0
+hub,2,0,%d
+end,3,0
+
+connections:
+start-north
+start-south
+north-hub
+south-hub
+hub-end capacity=2
+`
+
+// TestStationPlatforms checks that stations with several platforms let trains run in parallel
+func TestStationPlatforms(t *testing.T) {
+	testCases := []struct {
+		platforms int
+		turns     int
+	}{
+		{1, 6}, // All trains queue through the single platform of hub
+		{2, 4}, // Two trains pass hub in every turn
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d platforms", tc.platforms), func(t *testing.T) {
+			networks, err := io.ReadMap(writeMap(t, fmt.Sprintf(platformMap, tc.platforms)))
+			if err != nil {
+				t.Fatalf("Failed to read map: %v", err)
+			}
+			stations := networks["Platform Map"]
+
+			if capacity := stations["hub"].Capacity(); capacity != tc.platforms {
+				t.Errorf("Wanted %d platforms at hub, got %d", tc.platforms, capacity)
+			}
+
+			paths, _, err := pathfinding.FindPaths("start", "end", stations, 4)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if conflicts := core.FindConflicts(paths, stations); len(conflicts) > 0 {
+				t.Errorf("Planned paths have conflicts: %v", conflicts)
+			}
+
+			schedule, err := pathfinding.PlanSchedule("start", "end", stations, 4)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if schedule.Turns != tc.turns {
+				t.Errorf("Wanted %d turns, got %d", tc.turns, schedule.Turns)
+			}
+		})
+	}
+}
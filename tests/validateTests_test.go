@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"station/internal/core"
+	"station/internal/io"
+	"station/internal/utils"
+	"strings"
+	"testing"
+)
+
+// TestValidateLog checks hand-written movement logs against network.map and a weighted map
+func TestValidateLog(t *testing.T) {
+	mainPath, err := findMainGo()
+	if err != nil {
+		t.Fatalf("Failed to find main.go: %v", err)
+	}
+	networks, err := io.ReadMap(filepath.Join(filepath.Dir(mainPath), "network.map"))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	beginning := networks["Beginning to Terminus Map"]
+
+	weighted, err := io.ReadMap(writeMap(t, weightedMap))
+	if err != nil {
+		t.Fatalf("Failed to read map: %v", err)
+	}
+	london := weighted["Weighted Map"]
+
+	testCases := []struct {
+		name       string
+		log        string
+		start, end string
+		turns      int
+		expected   []string // Expected violations, as "T<n>: message" prefixes
+	}{
+		{"valid", "T1-terminus T2-near\nT2-far T3-terminus\nT2-terminus\n", "beginning", "terminus", 3, nil},
+		{"missing connection", "T1-far\nT1-terminus\n", "beginning", "terminus", 2, []string{"T1: no connection between beginning and far"}},
+		{"staggered trains", "T1-near\nT1-far T2-near\nT1-terminus T2-far\nT3-near\nT2-terminus T3-far\nT3-terminus\n", "beginning", "terminus", 6, nil},
+		{"station clash", "T1-near T2-near\nT1-far T2-far\nT1-terminus T2-terminus\n", "beginning", "terminus", 3, []string{
+			"T2: station near already occupied by T1",
+			"T2: track beginning-near used by 2 trains",
+			"T2: station far already occupied by T1",
+			"T2: track near-far used by 2 trains",
+			"T2: track far-terminus used by 2 trains",
+		}},
+		{"turned back", "T1-near\nT1-far\nT1-near\n", "beginning", "terminus", 3, []string{"T1: does not reach end station"}},
+		{"not finished", "T1-terminus\n\nT2-near\n", "beginning", "terminus", 3, []string{"T2: does not reach end station terminus"}},
+		{"too fast", "T1-victoria\nT1-st_pancras\n", "waterloo", "st_pancras", 2, []string{"T1: reaches victoria after 1 turn(s)"}},
+		{"transit", "T1-victoria(1/3)\nT1-victoria(2/3)\nT1-victoria\nT1-st_pancras\n", "waterloo", "st_pancras", 4, nil},
+		{"wrong transit", "T1-euston(1/3)\nT1-victoria(2/3)\nT1-victoria\nT1-st_pancras\n", "waterloo", "st_pancras", 4, []string{"T1: shown as euston(1/3)"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := io.ReadMovementLog(writeMap(t, tc.log))
+			if err != nil {
+				t.Fatalf("Failed to read log: %v", err)
+			}
+
+			stations := beginning
+			if tc.start == "waterloo" {
+				stations = london
+			}
+
+			turns, violations := core.ValidateLog(entries, tc.start, tc.end, stations)
+			if turns != tc.turns {
+				t.Errorf("Wanted %d turns, got %d", tc.turns, turns)
+			}
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, fmt.Sprintf("T%d: %s", v.TrainID+1, v.Message))
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Wanted violations %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tc.expected[i]) {
+					t.Errorf("Wanted violation starting with '%s', got '%s'", tc.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// TestInvalidMovementLog checks that malformed log entries are rejected with their line number
+func TestInvalidMovementLog(t *testing.T) {
+	_, err := io.ReadMovementLog(writeMap(t, "T1-near\nT2 far\n"))
+	var lineErr *utils.LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 || !errors.Is(err, utils.ErrInvalidLogEntry) {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+}